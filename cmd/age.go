@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// formatAge renders a duration the way kubectl prints ages: 45s, 5m10s, 3h20m,
+// 2d3h or 3y120d. Negative durations, such as the age of a timestamp in the
+// future, are formatted by magnitude and the caller phrases the direction.
+func formatAge(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+	return duration.HumanDuration(d)
+}
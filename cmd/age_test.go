@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatAge(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		input    time.Duration
+		expected string
+	}{
+		{0, "0s"},
+		{400 * time.Millisecond, "0s"},
+		{45 * time.Second, "45s"},
+		{5*time.Minute + 10*time.Second, "5m10s"},
+		{3*time.Hour + 20*time.Minute, "3h20m"},
+		{2*day + 3*time.Hour, "2d3h"},
+		{-(2*day + 3*time.Hour), "2d3h"},
+		{3*365*day + 120*day, "3y120d"},
+	}
+	for _, tt := range tests {
+		if got := formatAge(tt.input); got != tt.expected {
+			t.Errorf("formatAge(%s): expected %q, got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestDescribeExpiry(t *testing.T) {
+	if got := describeExpiry(time.Now().Add(50 * time.Hour)); got != "in 2d1h" && got != "in 2d2h" {
+		t.Errorf("unexpected future expiry %q", got)
+	}
+	if got := describeExpiry(time.Now().Add(-90 * time.Minute)); got != "expired 90m ago" {
+		t.Errorf("unexpected past expiry %q", got)
+	}
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/kube"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/logstats"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/messages"
+)
+
+var analyzeLogsCmd = &cobra.Command{
+	Use:     "analyze-logs <kind>/<name>",
+	GroupID: groupDebug,
+	Short:   "Summarize the error rate in the recent logs of a workload",
+	Long: `Summarize the error rate in the recent logs of a workload.
+
+Lines are classified as error, warn or other. The default rules recognize
+plain-text, logfmt, JSON and klog level markers; --error-pattern and
+--warn-pattern replace them with your own regular expressions. Error lines are
+grouped after masking numbers, timestamps and ids to show the most frequent
+messages.`,
+	Example: `  k8s-controller-cli analyze-logs deployment/web --since 1h
+  k8s-controller-cli analyze-logs deploy/web --error-pattern 'status=5\d\d' --max-error-rate 0.01`,
+	Args: strictArgs(cobra.ExactArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		if output != "text" && output != "json" {
+			return errs.ValidationFailed("unsupported output format %q, use text or json", output)
+		}
+		top, _ := cmd.Flags().GetInt("top")
+		maxErrorRate, _ := cmd.Flags().GetFloat64("max-error-rate")
+
+		rules, err := logRulesFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		workload, err := kube.ParseWorkload(args[0])
+		if err != nil {
+			return err
+		}
+		opts, err := logOptionsFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		client, err := kubeOptions.Clientset()
+		if err != nil {
+			return err
+		}
+		opts.Namespace = kubeOptions.ResolveNamespace()
+		opts.Selector, err = kube.PodSelector(cmd.Context(), client, opts.Namespace, workload)
+		if err != nil {
+			return err
+		}
+
+		analyzer := logstats.New(rules)
+		log.Info().Str("workload", workload.String()).Str("namespace", opts.Namespace).Dur("since", opts.Since).Msg("Analyzing logs")
+		err = kube.StreamLogs(cmd.Context(), client, opts, func(line kube.LogLine) {
+			if line.Err != nil {
+				log.Warn().Err(line.Err).Str("pod", line.Pod).Str("container", line.Container).Msg("Log stream failed")
+				return
+			}
+			analyzer.Add(line.Pod, line.Text)
+		})
+		if err != nil {
+			return err
+		}
+
+		summary := analyzer.Summary(top)
+		if output == "json" {
+			encoder := json.NewEncoder(stdout())
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(summary); err != nil {
+				return err
+			}
+		} else {
+			printLogSummary(summary)
+		}
+
+		if cmd.Flags().Changed("max-error-rate") && summary.ErrorRate > maxErrorRate {
+			return errs.New(errs.CodeUnknown, "error rate %.2f%% exceeds %.2f%%", summary.ErrorRate*100, maxErrorRate*100)
+		}
+		return nil
+	},
+}
+
+// logRulesFromFlags compiles --error-pattern and --warn-pattern, nil keeps the default rules.
+func logRulesFromFlags(cmd *cobra.Command) ([]logstats.Rule, error) {
+	var rules []logstats.Rule
+	for _, level := range []string{logstats.LevelError, logstats.LevelWarn} {
+		patterns, _ := cmd.Flags().GetStringArray(level + "-pattern")
+		for _, pattern := range patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, errs.Wrap(errs.CodeValidationFailed, err, "invalid --%s-pattern %q", level, pattern)
+			}
+			rules = append(rules, logstats.Rule{Level: level, Pattern: re})
+		}
+	}
+	return rules, nil
+}
+
+func printLogSummary(summary logstats.Summary) {
+	out := stdout()
+	printResult(messages.LogSummaryLines, summary.Lines)
+	printResult(messages.LogSummaryErrors, summary.Levels[logstats.LevelError])
+	printResult(messages.LogSummaryWarnings, summary.Levels[logstats.LevelWarn])
+	printResult(messages.LogSummaryErrorRate, summary.ErrorRate*100)
+
+	fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, messages.Get(messages.LogSummarySourcesHeader))
+	for _, s := range summary.Sources {
+		fmt.Fprintf(w, "%s\t%d\t%d\n", tableCell(s.Source), s.Lines, s.Errors)
+	}
+	w.Flush()
+
+	if len(summary.TopMessages) == 0 {
+		return
+	}
+	fmt.Fprintln(out)
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, messages.Get(messages.LogSummaryMessagesHeader))
+	for _, m := range summary.TopMessages {
+		fmt.Fprintf(w, "%d\t%s\n", m.Count, tableCell(m.Message))
+	}
+	w.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(analyzeLogsCmd)
+
+	addLogStreamFlags(analyzeLogsCmd, time.Hour)
+	analyzeLogsCmd.Flags().StringArray("error-pattern", nil, "Regular expression classifying a line as an error, repeatable (replaces the default rules)")
+	analyzeLogsCmd.Flags().StringArray("warn-pattern", nil, "Regular expression classifying a line as a warning, repeatable (replaces the default rules)")
+	analyzeLogsCmd.Flags().Int("top", 10, "Number of most frequent error messages to show")
+	analyzeLogsCmd.Flags().Float64("max-error-rate", 0, "Fail when the error rate is above this fraction, e.g. 0.01")
+	analyzeLogsCmd.Flags().StringP("output", "o", "text", "Output format: text or json")
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/kube"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/messages"
+)
+
+var authCmd = &cobra.Command{
+	Use:     "auth",
+	GroupID: groupCluster,
+	Short:   "Inspect kubeconfig credentials",
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the credential type and expiry of the current context",
+	Example: `  k8s-controller-cli auth status
+  k8s-controller-cli auth status --context prod --warn-within 72h`,
+	Args: strictArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		warnWithin, _ := cmd.Flags().GetDuration("warn-within")
+		output, _ := cmd.Flags().GetString("output")
+		if output != "text" && output != "json" {
+			return errs.ValidationFailed("unsupported output format %q, use text or json", output)
+		}
+
+		raw, err := kubeOptions.RawConfig()
+		if err != nil {
+			return err
+		}
+		status, err := kube.InspectCredentials(raw, kubeOptions.Context)
+		if err != nil {
+			return err
+		}
+
+		if status.Expiry != nil {
+			remaining := time.Until(*status.Expiry)
+			switch {
+			case remaining <= 0:
+				log.Error().Str("context", status.Context).Time("expiry", *status.Expiry).Msg("Credential has expired")
+			case remaining < warnWithin:
+				log.Warn().Str("context", status.Context).Dur("remaining", remaining).Msg("Credential expires soon")
+			}
+		}
+
+		if output == "json" {
+			encoder := json.NewEncoder(stdout())
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(status)
+		}
+
+		printResult(messages.AuthContext, status.Context)
+		printResult(messages.AuthCluster, status.Cluster)
+		printResult(messages.AuthUser, status.User)
+		printResult(messages.AuthCredential, status.Type)
+		if status.Subject != "" {
+			printResult(messages.AuthSubject, status.Subject)
+		}
+		if status.Expiry != nil {
+			expires := fmt.Sprintf("%s (%s)", status.Expiry.Format(time.RFC3339), describeExpiry(*status.Expiry))
+			printResult(messages.AuthExpires, colorize(expiryColor(time.Until(*status.Expiry), warnWithin), expires))
+		}
+		if status.Detail != "" {
+			printResult(messages.AuthDetail, status.Detail)
+		}
+		return nil
+	},
+}
+
+// expiryColor highlights expired credentials in red and those expiring within warnWithin in yellow.
+func expiryColor(remaining, warnWithin time.Duration) string {
+	switch {
+	case remaining <= 0:
+		return colorRed
+	case remaining < warnWithin:
+		return colorYellow
+	}
+	return ""
+}
+
+// describeExpiry renders the time left until expiry, e.g. "in 47h" or "expired 2d3h ago".
+func describeExpiry(expiry time.Time) string {
+	remaining := time.Until(expiry)
+	if remaining <= 0 {
+		return fmt.Sprintf("expired %s ago", formatAge(remaining))
+	}
+	return fmt.Sprintf("in %s", formatAge(remaining))
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authStatusCmd)
+
+	authStatusCmd.Flags().Duration("warn-within", 24*time.Hour, "Warn when the credential expires within this duration")
+	authStatusCmd.Flags().StringP("output", "o", "text", "Output format: text or json")
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/kube"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/messages"
+)
+
+var clustersCmd = &cobra.Command{
+	Use:     "clusters",
+	GroupID: groupCluster,
+	Short:   "Inspect the clusters configured in the kubeconfig",
+}
+
+var clustersStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Report reachability, version, nodes and controller installation of every context",
+	Example: `  k8s-controller-cli clusters status
+  k8s-controller-cli clusters status --contexts prod-a,prod-b -o json`,
+	Args: strictArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		contexts, _ := cmd.Flags().GetStringSlice("contexts")
+		output, _ := cmd.Flags().GetString("output")
+		controllerNamespace, _ := cmd.Flags().GetString("controller-namespace")
+		controllerName, _ := cmd.Flags().GetString("controller-name")
+		if output != "table" && output != "json" {
+			return errs.ValidationFailed("unsupported output format %q, use table or json", output)
+		}
+
+		targets, err := kubeOptions.ContextOptions(contexts)
+		if err != nil {
+			return err
+		}
+		raw, err := kubeOptions.RawConfig()
+		if err != nil {
+			return err
+		}
+
+		log.Info().Int("contexts", len(targets)).Msg("Probing clusters")
+		statuses := make([]kube.ClusterStatus, len(targets))
+		var wg sync.WaitGroup
+		for i, target := range targets {
+			statuses[i].Context = target.Context
+			if cluster, ok := raw.Clusters[raw.Contexts[target.Context].Cluster]; ok {
+				statuses[i].Server = cluster.Server
+			}
+
+			wg.Add(1)
+			go func(target kube.Options, status *kube.ClusterStatus) {
+				defer wg.Done()
+				probeContext(cmd.Context(), target, status, controllerNamespace, controllerName)
+			}(target, &statuses[i])
+		}
+		wg.Wait()
+		sort.Slice(statuses, func(i, j int) bool { return statuses[i].Context < statuses[j].Context })
+
+		if output == "json" {
+			encoder := json.NewEncoder(stdout())
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(statuses)
+		}
+
+		//rows are colored after alignment, escape codes would otherwise skew the column widths;
+		//cells are flattened so every status renders as exactly one line
+		var table bytes.Buffer
+		w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, messages.Get(messages.ClustersHeader))
+		for _, s := range statuses {
+			controller := s.Controller
+			if s.ControllerReady != "" {
+				controller += " (" + s.ControllerReady + ")"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%t\t%s\t%d/%d\t%s\t%s\n", tableCell(s.Context), tableCell(s.Server), s.Reachable, tableCell(s.ServerVersion), s.ReadyNodes, s.Nodes, tableCell(controller), tableCell(s.Error))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		lines := strings.SplitAfter(table.String(), "\n")
+		fmt.Fprint(stdout(), lines[0])
+		for i, s := range statuses {
+			fmt.Fprint(stdout(), colorize(clusterStatusColor(s), strings.TrimSuffix(lines[i+1], "\n"))+"\n")
+		}
+		return nil
+	},
+}
+
+// clusterStatusColor highlights unreachable clusters in red and degraded ones in yellow.
+func clusterStatusColor(s kube.ClusterStatus) string {
+	switch {
+	case !s.Reachable:
+		return colorRed
+	case s.Error != "" || s.ReadyNodes < s.Nodes || s.Controller == kube.ControllerUnknown:
+		return colorYellow
+	case s.ControllerReady != "":
+		ready, desired, _ := strings.Cut(s.ControllerReady, "/")
+		if ready != desired {
+			return colorYellow
+		}
+	}
+	return ""
+}
+
+// probeContext connects to a single context and records the result in status.
+func probeContext(ctx context.Context, target kube.Options, status *kube.ClusterStatus, controllerNamespace, controllerName string) {
+	client, err := target.Clientset()
+	if err != nil {
+		status.Error = err.Error()
+		status.Controller = kube.ControllerUnknown
+		return
+	}
+	kube.ProbeCluster(ctx, client, status, controllerNamespace, controllerName)
+	log.Debug().Str("context", status.Context).Bool("reachable", status.Reachable).Msg("Cluster probed")
+}
+
+func init() {
+	rootCmd.AddCommand(clustersCmd)
+	clustersCmd.AddCommand(clustersStatusCmd)
+
+	clustersStatusCmd.Flags().StringSlice("contexts", nil, "Contexts to probe (defaults to every context in the kubeconfig)")
+	clustersStatusCmd.Flags().StringP("output", "o", "table", "Output format: table or json")
+	clustersStatusCmd.Flags().String("controller-namespace", "k8s-controller-system", "Namespace of the controller deployment")
+	clustersStatusCmd.Flags().String("controller-name", "k8s-controller", "Name of the controller deployment")
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/convert"
+)
+
+var convertCmd = &cobra.Command{
+	Use:     "convert",
+	GroupID: groupGenerate,
+	Short:   "Convert between Pod, Deployment and StatefulSet manifests",
+	Long: `Convert between Pod, Deployment and StatefulSet manifests.
+
+The pod spec is carried over unchanged, including volumes and probes. Labels
+of a Pod become the template labels and the selector of the new workload, its
+annotations become template annotations. Fields set by the API server (status,
+uid, node name, injected service account volumes) are dropped so pods exported
+from a cluster can be converted directly.`,
+	Example: `  k8s-controller-cli convert -f pod.yaml --to deployment --replicas 3
+  k8s-controller-cli convert -f deployment.yaml --to statefulset --service-name web
+  kubectl get pod web -o yaml | k8s-controller-cli convert -f - --to deployment`,
+	Args: strictArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filename, _ := cmd.Flags().GetString("filename")
+		to, _ := cmd.Flags().GetString("to")
+		output, _ := cmd.Flags().GetString("output")
+
+		kind, err := convert.ParseKind(to)
+		if err != nil {
+			return err
+		}
+		var opts convert.Options
+		opts.ServiceName, _ = cmd.Flags().GetString("service-name")
+		if cmd.Flags().Changed("replicas") {
+			replicas, _ := cmd.Flags().GetInt32("replicas")
+			opts.Replicas = &replicas
+		}
+
+		data, err := readManifestFile(filename)
+		if err != nil {
+			return err
+		}
+		converted, err := convert.ConvertManifest(data, kind, opts)
+		if err != nil {
+			return err
+		}
+		log.Info().Str("to", kind).Msg("Manifest converted")
+		return writeOutput(output, converted)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(convertCmd)
+
+	convertCmd.Flags().StringP("filename", "f", "", "Manifest to convert, - for stdin")
+	convertCmd.Flags().String("to", "", "Target kind: pod, deployment or statefulset")
+	convertCmd.Flags().Int32("replicas", 0, "Replica count of the converted workload (defaults to the source's)")
+	convertCmd.Flags().String("service-name", "", "Governing service of a StatefulSet (defaults to the source's or the object name)")
+	convertCmd.Flags().StringP("output", "o", "", "Output file, defaults to stdout")
+	_ = convertCmd.MarkFlagRequired("filename")
+	_ = convertCmd.MarkFlagRequired("to")
+}
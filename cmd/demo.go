@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+)
+
+var demoStateFile string
+
+// demoState is the on-disk form of the demo cluster, NodeNumber can't be serialized so the count is kept aside.
+type demoState struct {
+	Cluster Kubernetes `json:"cluster"`
+	Nodes   int        `json:"nodes"`
+}
+
+var demoCmd = &cobra.Command{
+	Use:     "demo",
+	GroupID: groupDemo,
+	Short:   "Demo commands operating on a local example cluster",
+	Long: `Demo commands operating on a local example cluster.
+
+The example cluster is stored in a JSON state file, so users and nodes added
+by one invocation are visible to the next. Use "demo reset" to start over.`,
+}
+
+var demoResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Reset the demo cluster to its initial state",
+	Args:  strictArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := os.Remove(demoStateFile); err != nil && !os.IsNotExist(err) {
+			return errs.Wrap(errs.CodeUnknown, err, "removing demo state %s", demoStateFile)
+		}
+		log.Info().Str("state_file", demoStateFile).Msg("Demo cluster reset")
+		return nil
+	},
+}
+
+// newDemoCluster returns the example cluster used when no state has been saved yet.
+func newDemoCluster() Kubernetes {
+	return Kubernetes{
+		Name:    "k8s-demo-cluster",
+		Version: "1.31",
+		Users:   []string{"alex", "den"},
+		NodeNumber: func() int {
+			return 10
+		},
+	}
+}
+
+// loadDemoCluster reads the demo cluster from path, returning a fresh one if the file doesn't exist
+// or state persistence is disabled.
+func loadDemoCluster(path string) (Kubernetes, error) {
+	if !featureGates.Enabled(featureDemoStatePersistence) {
+		return newDemoCluster(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return newDemoCluster(), nil
+	}
+	if err != nil {
+		return Kubernetes{}, errs.Wrap(errs.CodeUnknown, err, "reading demo state %s", path)
+	}
+
+	var state demoState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return Kubernetes{}, errs.Wrap(errs.CodeValidationFailed, err, "parsing demo state %s", path)
+	}
+
+	k8s := state.Cluster
+	nodes := state.Nodes
+	k8s.NodeNumber = func() int {
+		return nodes
+	}
+	log.Debug().Str("state_file", path).Msg("Loaded demo cluster state")
+	return k8s, nil
+}
+
+// saveDemoCluster writes the demo cluster to path, creating the parent directory if needed.
+// It does nothing when state persistence is disabled.
+func saveDemoCluster(path string, k8s Kubernetes) error {
+	if !featureGates.Enabled(featureDemoStatePersistence) {
+		return nil
+	}
+
+	state := demoState{Cluster: k8s}
+	if k8s.NodeNumber != nil {
+		state.Nodes = k8s.NodeNumber()
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return errs.Wrap(errs.CodeUnknown, err, "encoding demo state")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return errs.Wrap(errs.CodeUnknown, err, "creating demo state directory")
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return errs.Wrap(errs.CodeUnknown, err, "writing demo state %s", path)
+	}
+	log.Debug().Str("state_file", path).Msg("Saved demo cluster state")
+	return nil
+}
+
+// deprecatedDemoAlias returns a hidden top-level copy of a demo command, so
+// scripts written before the commands moved under demo keep working for one
+// more release.
+func deprecatedDemoAlias(cmd *cobra.Command) *cobra.Command {
+	alias := &cobra.Command{
+		Use:        cmd.Use,
+		GroupID:    groupDemo,
+		Aliases:    cmd.Aliases,
+		Short:      cmd.Short,
+		Args:       cmd.Args,
+		Run:        cmd.Run,
+		RunE:       cmd.RunE,
+		Hidden:     true,
+		Deprecated: fmt.Sprintf("use \"demo %s\" instead", cmd.Name()),
+	}
+	alias.Flags().AddFlagSet(cmd.Flags())
+	alias.Flags().StringVar(&demoStateFile, "state-file", filepath.Join(stateDir(), "demo.json"), "Path to the demo cluster state file")
+	return alias
+}
+
+func init() {
+	rootCmd.AddCommand(demoCmd)
+	demoCmd.AddCommand(goBasicCmd)
+	demoCmd.AddCommand(addNewUser)
+	demoCmd.AddCommand(describeCluster)
+	demoCmd.AddCommand(defineNodeCount)
+	demoCmd.AddCommand(demoResetCmd)
+
+	demoCmd.PersistentFlags().StringVar(&demoStateFile, "state-file", filepath.Join(stateDir(), "demo.json"), "Path to the demo cluster state file")
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadDemoClusterDefaults(t *testing.T) {
+	k8s, err := loadDemoCluster(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if k8s.Name != "k8s-demo-cluster" {
+		t.Errorf("expected default cluster name, got %s", k8s.Name)
+	}
+	if k8s.NodeNumber() != 10 {
+		t.Errorf("expected 10 default nodes, got %d", k8s.NodeNumber())
+	}
+}
+
+func TestDemoClusterStatePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "demo.json")
+
+	k8s := newDemoCluster()
+	k8s.AddNewUser("charlie")
+	k8s.defineNodeCount(12)
+	if err := saveDemoCluster(path, k8s); err != nil {
+		t.Fatalf("failed to save state: %v", err)
+	}
+
+	loaded, err := loadDemoCluster(path)
+	if err != nil {
+		t.Fatalf("failed to load state: %v", err)
+	}
+
+	expected := []string{"alex", "den", "charlie"}
+	if len(loaded.Users) != len(expected) {
+		t.Fatalf("expected users %v, got %v", expected, loaded.Users)
+	}
+	for i, user := range expected {
+		if loaded.Users[i] != user {
+			t.Errorf("expected user %s at index %d, got %s", user, i, loaded.Users[i])
+		}
+	}
+	if loaded.NodeNumber() != 12 {
+		t.Errorf("expected 12 nodes, got %d", loaded.NodeNumber())
+	}
+}
+
+func TestDeprecatedDemoAliases(t *testing.T) {
+	stateFile := demoStateFile
+	t.Cleanup(func() { demoStateFile = stateFile })
+	state := filepath.Join(t.TempDir(), "demo.json")
+	output, err := executeRoot(t, "add-node", "2", "--state-file", state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(output, `use "demo add-node" instead`) {
+		t.Errorf("expected a deprecation notice, got %q", output)
+	}
+
+	loaded, err := loadDemoCluster(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.NodeNumber() != 12 {
+		t.Errorf("expected the alias to add 2 nodes, got %d", loaded.NodeNumber())
+	}
+
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Deprecated != "" && !cmd.Hidden {
+			t.Errorf("expected deprecated command %s to be hidden", cmd.Name())
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/kube"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+var generateKubeconfigCmd = &cobra.Command{
+	Use:     "generate-kubeconfig",
+	GroupID: groupGenerate,
+	Short:   "Generate a standalone kubeconfig for a service account",
+	Long: `Generate a standalone kubeconfig for a service account.
+
+The kubeconfig points at the server and CA of the current context and
+authenticates with a token of the service account: a time-bound token from the
+TokenRequest API by default, or a long-lived service-account-token Secret
+with --long-lived.`,
+	Example: `  k8s-controller-cli generate-kubeconfig --serviceaccount deployer -n ci > ci.kubeconfig
+  k8s-controller-cli generate-kubeconfig --serviceaccount deployer -n ci --create --duration 720h -o ci.kubeconfig`,
+	Args: strictArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("serviceaccount")
+		create, _ := cmd.Flags().GetBool("create")
+		longLived, _ := cmd.Flags().GetBool("long-lived")
+		duration, _ := cmd.Flags().GetDuration("duration")
+		clusterName, _ := cmd.Flags().GetString("cluster-name")
+		output, _ := cmd.Flags().GetString("output")
+		switch {
+		case longLived && cmd.Flags().Changed("duration"):
+			return errs.ValidationFailed("--duration can't be combined with --long-lived, the Secret's token doesn't expire")
+		case !longLived && duration < 10*time.Minute:
+			return errs.ValidationFailed("--duration must be at least 10m, got %s", duration)
+		}
+
+		restConfig, err := kubeOptions.RESTConfig()
+		if err != nil {
+			return err
+		}
+		client, err := kubeOptions.Clientset()
+		if err != nil {
+			return err
+		}
+		if clusterName == "" {
+			clusterName = currentClusterName()
+		}
+
+		opts := kube.ServiceAccountOptions{
+			Namespace:   kubeOptions.ResolveNamespace(),
+			Name:        name,
+			Create:      create,
+			LongLived:   longLived,
+			Duration:    duration,
+			ClusterName: clusterName,
+		}
+		log.Info().Str("namespace", opts.Namespace).Str("serviceaccount", opts.Name).Bool("long_lived", longLived).Msg("Generating kubeconfig")
+
+		config, err := kube.ServiceAccountKubeconfig(cmd.Context(), client, restConfig, opts)
+		if err != nil {
+			return err
+		}
+		data, err := clientcmd.Write(*config)
+		if err != nil {
+			return errs.Wrap(errs.CodeUnknown, err, "encoding kubeconfig")
+		}
+
+		if output == "" || output == "-" {
+			_, err = stdout().Write(data)
+			return err
+		}
+		//the kubeconfig holds a credential, keep it private
+		if err := os.WriteFile(output, data, 0o600); err != nil {
+			return errs.Wrap(errs.CodeUnknown, err, "writing %s", output)
+		}
+		log.Info().Str("output", output).Msg("Kubeconfig generated successfully")
+		return nil
+	},
+}
+
+// currentClusterName returns the cluster name of the selected context, or "cluster" if it can't be determined.
+func currentClusterName() string {
+	raw, err := kubeOptions.RawConfig()
+	if err != nil {
+		return "cluster"
+	}
+	contextName := kubeOptions.Context
+	if contextName == "" {
+		contextName = raw.CurrentContext
+	}
+	if c, ok := raw.Contexts[contextName]; ok && c.Cluster != "" {
+		return c.Cluster
+	}
+	return "cluster"
+}
+
+func init() {
+	rootCmd.AddCommand(generateKubeconfigCmd)
+
+	generateKubeconfigCmd.Flags().String("serviceaccount", "", "Service account to authenticate as")
+	generateKubeconfigCmd.Flags().Bool("create", false, "Create the service account if it doesn't exist")
+	generateKubeconfigCmd.Flags().Bool("long-lived", false, "Use a non-expiring service-account-token Secret instead of a TokenRequest")
+	generateKubeconfigCmd.Flags().Duration("duration", 24*time.Hour, "Lifetime of the requested token, not allowed with --long-lived")
+	generateKubeconfigCmd.Flags().String("cluster-name", "", "Cluster name in the generated kubeconfig (defaults to the current context's cluster)")
+	generateKubeconfigCmd.Flags().StringP("output", "o", "", "Write the kubeconfig to a file instead of stdout")
+	_ = generateKubeconfigCmd.MarkFlagRequired("serviceaccount")
+}
@@ -0,0 +1,14 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+)
+
+func TestGenerateKubeconfigRejectsDurationWithLongLived(t *testing.T) {
+	_, err := executeRoot(t, "generate-kubeconfig", "--serviceaccount", "deployer", "--long-lived", "--duration", "1h")
+	if !errs.IsValidationFailed(err) {
+		t.Errorf("expected validation error, got %v", err)
+	}
+}
@@ -1,28 +1,28 @@
 package cmd
 
 import (
-	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/builder"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/manifest"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/messages"
 )
 
 var goBasicCmd = &cobra.Command{
-	Use:   "go-basic",
-	Short: "Run golang basic code",
+	Use:     "go-basic",
+	Aliases: []string{"basic"},
+	Short:   "Run golang basic code",
+	Example: `  k8s-controller-cli demo go-basic`,
+	Args:    strictArgs(cobra.NoArgs),
 	Run: func(cmd *cobra.Command, args []string) {
 		log.Info().Msg("Starting go-basic command")
 
-		//Go basic code to run functions
-		k8s := Kubernetes{
-			Name:    "k8s-demo-cluster",
-			Version: "1.31",
-			Users:   []string{"alex", "den"},
-			NodeNumber: func() int {
-				return 10
-			},
-		}
+		//Go basic code to run functions, on a fresh cluster so the walkthrough is repeatable
+		k8s := newDemoCluster()
 
 		log.Info().Str("cluster", k8s.Name).Str("version", k8s.Version).Msg("Initialized Kubernetes cluster")
 
@@ -43,28 +43,21 @@ var goBasicCmd = &cobra.Command{
 }
 
 var addNewUser = &cobra.Command{
-	Use:   "add-user [user]",
-	Short: "Add new user to kubernetes cluster",
-	Run: func(cmd *cobra.Command, args []string) {
+	Use:     "add-user [user...]",
+	Aliases: []string{"user"},
+	Short:   "Add new user to kubernetes cluster",
+	Example: `  k8s-controller-cli demo add-user alice
+  k8s-controller-cli demo add-user alice bob`,
+	Args: strictArgs(cobra.MinimumNArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
 		log.Info().Strs("users", args).Msg("Starting add-user command")
 
-		if len(args) < 1 {
-			log.Error().Msg("No username provided")
-			fmt.Println("Error: please provide a username")
-			return
-		}
-
-		//Go basic code to run functions
-		k8s := Kubernetes{
-			Name:    "k8s-demo-cluster",
-			Version: "1.31",
-			Users:   []string{"alex", "den"},
-			NodeNumber: func() int {
-				return 10
-			},
+		k8s, err := loadDemoCluster(demoStateFile)
+		if err != nil {
+			return err
 		}
 
-		log.Info().Str("cluster", k8s.Name).Msg("Initialized Kubernetes cluster")
+		log.Info().Str("cluster", k8s.Name).Msg("Loaded Kubernetes cluster")
 
 		//add new user to struct
 		for _, username := range args {
@@ -76,63 +69,114 @@ var addNewUser = &cobra.Command{
 		log.Info().Msg("Getting updated users list")
 		k8s.GetUsers()
 
+		if err := saveDemoCluster(demoStateFile, k8s); err != nil {
+			return err
+		}
+
 		log.Info().Int("total_users", len(k8s.Users)).Msg("add-user command completed successfully")
+		return nil
 	},
 }
 
 var describeCluster = &cobra.Command{
-	Use:   "describe-cluster",
-	Short: "Describe kubernetes cluster",
-	Run: func(cmd *cobra.Command, args []string) {
+	Use:     "describe-cluster",
+	Aliases: []string{"describe"},
+	Short:   "Describe kubernetes cluster",
+	Example: `  k8s-controller-cli demo describe-cluster`,
+	Args:    strictArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
 		log.Info().Msg("Starting describe-cluster command")
 
-		k8s := Kubernetes{
-			Name:    "k8s-demo-cluster",
-			Version: "1.31",
-			Users:   []string{"alex", "den"},
+		k8s, err := loadDemoCluster(demoStateFile)
+		if err != nil {
+			return err
 		}
 
 		log.Info().Str("cluster", k8s.Name).Str("version", k8s.Version).Int("users_count", len(k8s.Users)).Msg("Describing cluster")
 		k8s.GetClusterInfo()
 
 		log.Info().Msg("describe-cluster command completed successfully")
+		return nil
 	},
 }
 
 var defineNodeCount = &cobra.Command{
-	Use:   "add-node",
-	Short: "Add node to kubernetes cluster",
-	Run: func(cmd *cobra.Command, args []string) {
-		nodeCount, err := strconv.Atoi(args[0])
+	Use:     "add-node [count]",
+	Aliases: []string{"node"},
+	Short:   "Add node to kubernetes cluster",
+	Example: `  k8s-controller-cli demo add-node 3
+  k8s-controller-cli demo add-node --count 3 --max 5`,
+	Args: strictArgs(cobra.MaximumNArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		count, _ := cmd.Flags().GetInt("count")
+		minCount, _ := cmd.Flags().GetInt("min")
+		maxCount, _ := cmd.Flags().GetInt("max")
+
+		nodeCount, err := parseNodeCount(args, count, cmd.Flags().Changed("count"), minCount, maxCount)
 		if err != nil {
-			log.Error().Msg("Invalid node count! Please provide a valid number.")
-			return
+			return err
 		}
 
-		k8s := Kubernetes{
-			Name:    "k8s-demo-cluster",
-			Version: "1.31",
-			Users:   []string{"alex", "den"},
+		k8s, err := loadDemoCluster(demoStateFile)
+		if err != nil {
+			return err
 		}
 
-		k8s.defineNodeCount(nodeCount)
+		k8s.defineNodeCount(k8s.NodeNumber() + nodeCount)
+		if err := saveDemoCluster(demoStateFile, k8s); err != nil {
+			return err
+		}
 
 		log.Info().Msg("add-node command completed successfully")
+		return nil
 	},
 }
 
+// parseNodeCount resolves the node count from either the positional argument or --count and checks it against the bounds.
+func parseNodeCount(args []string, count int, countSet bool, minCount, maxCount int) (int, error) {
+	if minCount > maxCount {
+		return 0, errs.ValidationFailed("--min (%d) must not be greater than --max (%d)", minCount, maxCount)
+	}
+
+	switch {
+	case len(args) > 0 && countSet:
+		return 0, errs.ValidationFailed("provide the node count either as an argument or with --count, not both")
+	case len(args) > 0:
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			return 0, errs.Wrap(errs.CodeValidationFailed, err, "invalid node count %q", args[0])
+		}
+		count = n
+	case !countSet:
+		return 0, errs.ValidationFailed("please provide a node count")
+	}
+
+	if count < minCount || count > maxCount {
+		return 0, errs.ValidationFailed("node count %d is out of bounds [%d, %d]", count, minCount, maxCount)
+	}
+	return count, nil
+}
+
 func init() {
-	rootCmd.AddCommand(goBasicCmd)
-	rootCmd.AddCommand(addNewUser)
-	rootCmd.AddCommand(describeCluster)
-	rootCmd.AddCommand(defineNodeCount)
 	rootCmd.AddCommand(createPod)
 
+	// Add flags for add-node command
+	defineNodeCount.Flags().Int("count", 0, "Number of nodes to add")
+	defineNodeCount.Flags().Int("min", 1, "Minimum number of nodes that can be added at once")
+	defineNodeCount.Flags().Int("max", 100, "Maximum number of nodes that can be added at once")
+
+	// The demo commands used to be top-level, keep hidden aliases for one release
+	for _, cmd := range []*cobra.Command{goBasicCmd, addNewUser, describeCluster, defineNodeCount} {
+		rootCmd.AddCommand(deprecatedDemoAlias(cmd))
+	}
+
 	// Add flags for create-pod command
 	createPod.Flags().String("name", "", "Pod name")
 	createPod.Flags().String("image", "", "Container image")
 	createPod.Flags().String("tag", "", "Image tag")
 	createPod.Flags().Int("port", 0, "Container port")
+	createPod.Flags().Bool("validate-only", false, "Validate the input and print the pod manifest without creating it")
+	addPodFlags(createPod.Flags())
 }
 
 type Kubernetes struct {
@@ -150,9 +194,17 @@ type Pod struct {
 }
 
 var createPod = &cobra.Command{
-	Use:   "create-pod",
-	Short: "Create a pod in the Kubernetes cluster",
-	Run: func(cmd *cobra.Command, args []string) {
+	Use:     "create-pod",
+	Aliases: []string{"pod"},
+	GroupID: groupCluster,
+	Short:   "Create a pod in the Kubernetes cluster",
+	Example: `  k8s-controller-cli create-pod --name web --image nginx --tag 1.27 --port 80
+  k8s-controller-cli create-pod --name web --image nginx --tag 1.27 --port 80 --validate-only
+  k8s-controller-cli create-pod --name web --image nginx --tag 1.27 --port 80 --secret web-tls:/tls --secret-env DB_PASSWORD=db:password
+  k8s-controller-cli create-pod --name web --image nginx --tag 1.27 --port 80 --node-selector disktype=ssd --toleration dedicated=web:NoSchedule
+  k8s-controller-cli create-pod --name web --image nginx --tag 1.27 --port 8080 --run-as-user 1000 --run-as-non-root --read-only-root-fs --drop-capabilities ALL --validate-only`,
+	Args: strictArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
 		log.Info().Msg("Starting create-pod command")
 
 		name, _ := cmd.Flags().GetString("name")
@@ -161,9 +213,7 @@ var createPod = &cobra.Command{
 		port, _ := cmd.Flags().GetInt("port")
 
 		if name == "" || image == "" || tag == "" || port == 0 {
-			log.Error().Msg("Missing required flags: --name, --image, --tag, --port")
-			fmt.Println("Error: please provide a name, image, tag, and port")
-			return
+			return errs.ValidationFailed("please provide a name, image, tag, and port")
 		}
 
 		pod := Pod{
@@ -172,16 +222,52 @@ var createPod = &cobra.Command{
 			ImageTag:  tag,
 			Port:      port,
 		}
+		if err := pod.Validate(); err != nil {
+			return err
+		}
+
+		b := builder.NewPod(pod.Name).
+			Image(pod.ImageRepo + ":" + pod.ImageTag).
+			Port(int32(pod.Port))
+		if err := applyPodFlags(cmd.Flags(), b); err != nil {
+			return err
+		}
+		built, err := b.Build()
+		if err != nil {
+			return err
+		}
+
+		if validateOnly, _ := cmd.Flags().GetBool("validate-only"); validateOnly {
+			log.Info().Str("name", pod.Name).Msg("Pod configuration is valid")
+			data, err := manifest.Marshal(built)
+			if err != nil {
+				return err
+			}
+			_, err = stdout().Write(data)
+			return err
+		}
 
 		log.Info().Str("name", pod.Name).Str("image", pod.ImageRepo).Str("tag", pod.ImageTag).Int("port", pod.Port).Msg("Creating pod...")
 		// Add logic to create the pod in the Kubernetes cluster
+		return nil
 	},
 }
 
+// Validate checks the pod fields that must be set before the pod can be created.
+func (p Pod) Validate() error {
+	if p.Port < 1 || p.Port > 65535 {
+		return errs.ValidationFailed("port %d is out of range [1, 65535]", p.Port)
+	}
+	if strings.ContainsAny(p.ImageTag, ":@/") {
+		return errs.ValidationFailed("tag %q must not contain ':', '@' or '/'", p.ImageTag)
+	}
+	return nil
+}
+
 func (k8s Kubernetes) GetUsers() {
 	log.Info().Int("users_count", len(k8s.Users)).Msg("Getting users list")
 	for _, user := range k8s.Users {
-		fmt.Println(user)
+		printResult(messages.UserEntry, user)
 	}
 	log.Info().Msg("Users list displayed successfully")
 }
@@ -194,8 +280,8 @@ func (k8s *Kubernetes) AddNewUser(user string) {
 
 func (k8s *Kubernetes) GetClusterInfo() {
 	log.Info().Str("cluster", k8s.Name).Str("version", k8s.Version).Msg("Getting cluster information")
-	fmt.Println("Cluster name: ", k8s.Name)
-	fmt.Println("Kubernetes version: ", k8s.Version)
+	printResult(messages.ClusterName, k8s.Name)
+	printResult(messages.ClusterVersion, k8s.Version)
 	log.Info().Msg("Cluster information displayed successfully")
 }
 
@@ -204,10 +290,6 @@ func (k8s *Kubernetes) defineNodeCount(nodeCount int) {
 	k8s.NodeNumber = func() int {
 		return nodeCount
 	}
-	fmt.Println("Current number of nodes on cluster is: ", nodeCount)
-	if log.Info() != nil {
-		log.Info().Msg("Node count defined successfully")
-	} else {
-		log.Error().Msg("Node count not defined")
-	}
+	printInfo(messages.NodeCountDefined, nodeCount)
+	log.Info().Msg("Node count defined successfully")
 }
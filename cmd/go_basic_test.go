@@ -2,8 +2,14 @@ package cmd
 
 import (
 	"bytes"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
 )
 
 func TestAddNewUser(t *testing.T) {
@@ -52,3 +58,77 @@ func TestGetUsers(t *testing.T) {
 		t.Errorf("expected output %q, got %q", expected, output)
 	}
 }
+
+func TestParseNodeCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		count    int
+		countSet bool
+		min, max int
+		expected int
+		wantErr  bool
+	}{
+		{"positional argument", []string{"3"}, 0, false, 1, 100, 3, false},
+		{"count flag", nil, 5, true, 1, 100, 5, false},
+		{"no arguments", nil, 0, false, 1, 100, 0, true},
+		{"not a number", []string{"three"}, 0, false, 1, 100, 0, true},
+		{"argument and flag", []string{"3"}, 3, true, 1, 100, 0, true},
+		{"below min", []string{"0"}, 0, false, 1, 100, 0, true},
+		{"above max", nil, 6, true, 1, 5, 0, true},
+		{"min greater than max", []string{"3"}, 0, false, 5, 1, 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseNodeCount(tt.args, tt.count, tt.countSet, tt.min, tt.max)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error, got %d", tt.name, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestAddNodeWithoutArgs(t *testing.T) {
+	state := filepath.Join(t.TempDir(), "demo.json")
+	rootCmd.SetArgs([]string{"demo", "add-node", "--state-file", state})
+	rootCmd.SetOut(io.Discard)
+	rootCmd.SetErr(io.Discard)
+	logger := log.Logger
+	defer func() {
+		log.Logger = logger
+		rootCmd.SetArgs(nil)
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+	}()
+
+	err := rootCmd.Execute()
+	if !errs.IsValidationFailed(err) {
+		t.Errorf("expected validation error, got %v", err)
+	}
+}
+
+func TestCreatePodValidateOnly(t *testing.T) {
+	output, err := executeRoot(t, "create-pod", "--name", "web", "--image", "nginx", "--tag", "1.27", "--port", "80", "--validate-only")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"kind: Pod", "name: web", "image: nginx:1.27", "containerPort: 80"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got %q", want, output)
+		}
+	}
+
+	_, err = executeRoot(t, "create-pod", "--name", "Web_1", "--image", "nginx", "--tag", "1.27", "--port", "80", "--validate-only")
+	if !errs.IsValidationFailed(err) {
+		t.Errorf("expected validation error for an invalid name, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Command groups shown in the root help output.
+const (
+	// groupGenerate holds commands producing or transforming files locally
+	groupGenerate = "generate"
+	// groupCluster holds commands acting on cluster objects and access
+	groupCluster = "cluster"
+	// groupController holds commands about the CLI itself
+	groupController = "controller"
+	// groupDebug holds read-only troubleshooting commands
+	groupDebug = "debug"
+	// groupDemo holds the walkthrough commands working on a local example cluster
+	groupDemo = "demo"
+)
+
+// usageTemplate is cobra's default usage template with aliases listed next to each command name.
+var usageTemplate = `Usage:{{if .Runnable}}
+  {{.UseLine}}{{end}}{{if .HasAvailableSubCommands}}
+  {{.CommandPath}} [command]{{end}}{{if gt (len .Aliases) 0}}
+
+Aliases:
+  {{.NameAndAliases}}{{end}}{{if .HasExample}}
+
+Examples:
+{{.Example}}{{end}}{{if .HasAvailableSubCommands}}{{$cmds := .Commands}}{{if eq (len .Groups) 0}}
+
+Available Commands:{{range $cmds}}{{if (or .IsAvailableCommand (eq .Name "help"))}}
+  {{rpad (nameWithAliases .) (aliasPadding .)}} {{.Short}}{{end}}{{end}}{{else}}{{range $group := .Groups}}
+
+{{.Title}}{{range $cmds}}{{if (and (eq .GroupID $group.ID) (or .IsAvailableCommand (eq .Name "help")))}}
+  {{rpad (nameWithAliases .) (aliasPadding .)}} {{.Short}}{{end}}{{end}}{{end}}{{if not .AllChildCommandsHaveGroup}}
+
+Additional Commands:{{range $cmds}}{{if (and (eq .GroupID "") (or .IsAvailableCommand (eq .Name "help")))}}
+  {{rpad (nameWithAliases .) (aliasPadding .)}} {{.Short}}{{end}}{{end}}{{end}}{{end}}{{end}}{{if .HasAvailableLocalFlags}}
+
+Flags:
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasAvailableInheritedFlags}}
+
+Global Flags:
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasHelpSubCommands}}
+
+Additional help topics:{{range .Commands}}{{if .IsAdditionalHelpTopicCommand}}
+  {{rpad .CommandPath .CommandPathPadding}} {{.Short}}{{end}}{{end}}{{end}}{{if .HasAvailableSubCommands}}
+
+Use "{{.CommandPath}} [command] --help" for more information about a command.{{end}}
+`
+
+// nameWithAliases renders a command name followed by its aliases, e.g. "describe-cluster (describe)".
+func nameWithAliases(cmd *cobra.Command) string {
+	if len(cmd.Aliases) == 0 {
+		return cmd.Name()
+	}
+	return cmd.Name() + " (" + strings.Join(cmd.Aliases, ", ") + ")"
+}
+
+// aliasPadding returns the column width needed to align nameWithAliases across sibling commands.
+func aliasPadding(cmd *cobra.Command) int {
+	if !cmd.HasParent() {
+		return len(nameWithAliases(cmd))
+	}
+	padding := 0
+	for _, sibling := range cmd.Parent().Commands() {
+		// hidden commands aren't listed and shouldn't widen the column
+		if !sibling.IsAvailableCommand() && sibling.Name() != "help" {
+			continue
+		}
+		if l := len(nameWithAliases(sibling)); l > padding {
+			padding = l
+		}
+	}
+	return padding
+}
+
+func init() {
+	cobra.AddTemplateFunc("nameWithAliases", nameWithAliases)
+	cobra.AddTemplateFunc("aliasPadding", aliasPadding)
+
+	rootCmd.AddGroup(
+		&cobra.Group{ID: groupGenerate, Title: "Generate Commands:"},
+		&cobra.Group{ID: groupCluster, Title: "Cluster Commands:"},
+		&cobra.Group{ID: groupController, Title: "Controller Commands:"},
+		&cobra.Group{ID: groupDebug, Title: "Debug Commands:"},
+		&cobra.Group{ID: groupDemo, Title: "Demo Commands:"},
+	)
+	rootCmd.SetHelpCommandGroupID(groupController)
+	rootCmd.SetCompletionCommandGroupID(groupController)
+	rootCmd.SetUsageTemplate(usageTemplate)
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import "testing"
+
+func TestRootCommandsAreGrouped(t *testing.T) {
+	groups := map[string]bool{}
+	for _, group := range rootCmd.Groups() {
+		groups[group.ID] = true
+	}
+	for _, cmd := range rootCmd.Commands() {
+		// cobra adds these itself
+		if cmd.Name() == "help" || cmd.Name() == "completion" {
+			continue
+		}
+		if !groups[cmd.GroupID] {
+			t.Errorf("command %s has no help group", cmd.Name())
+		}
+	}
+}
+
+func TestAliasPaddingIgnoresHiddenCommands(t *testing.T) {
+	expected := 0
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.IsAvailableCommand() && len(nameWithAliases(cmd)) > expected {
+			expected = len(nameWithAliases(cmd))
+		}
+	}
+	if padding := aliasPadding(versionCmd); padding != expected {
+		t.Errorf("expected the listed commands to set the padding to %d, got %d", expected, padding)
+	}
+}
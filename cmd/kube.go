@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/kube"
+)
+
+// kubeOptions holds the connection flags shared by all commands that talk to a cluster.
+var kubeOptions kube.Options
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&kubeOptions.Kubeconfig, "kubeconfig", "", "Path to the kubeconfig file (defaults to $KUBECONFIG, then ~/.kube/config)")
+	rootCmd.PersistentFlags().StringVar(&kubeOptions.Context, "context", "", "Kubeconfig context to use (defaults to the current context)")
+	rootCmd.PersistentFlags().StringVarP(&kubeOptions.Namespace, "namespace", "n", "", "Namespace to use (defaults to $POD_NAMESPACE, $NAMESPACE, the context's namespace, then \"default\")")
+	rootCmd.PersistentFlags().DurationVar(&kubeOptions.Timeout, "request-timeout", 30*time.Second, "Timeout for a single API request")
+}
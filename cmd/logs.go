@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"os/signal"
+	"regexp"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/kube"
+)
+
+// logColors are the ANSI colors used for pod prefixes, picked by hashing the pod name.
+var logColors = []string{"31", "32", "33", "34", "35", "36", "91", "92", "93", "94", "95", "96"}
+
+var logsCmd = &cobra.Command{
+	Use:     "logs <kind>/<name>",
+	GroupID: groupDebug,
+	Short:   "Print the logs of every pod of a workload",
+	Long: `Print the logs of every container in every pod of a deployment, statefulset
+or daemonset, each line prefixed with its pod and container.
+
+With --follow, pods created during a rollout are picked up as they start and
+restarted containers are streamed again. --since and --tail only apply to the
+containers already running when the command starts.`,
+	Example: `  k8s-controller-cli logs deployment/web -n payments
+  k8s-controller-cli logs deploy/web --follow --grep 'level=error'
+  k8s-controller-cli logs sts/db -c postgres --since 10m`,
+	Args: strictArgs(cobra.ExactArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workload, err := kube.ParseWorkload(args[0])
+		if err != nil {
+			return err
+		}
+		opts, err := logOptionsFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		client, err := kubeOptions.Clientset()
+		if err != nil {
+			return err
+		}
+		opts.Namespace = kubeOptions.ResolveNamespace()
+		opts.Selector, err = kube.PodSelector(cmd.Context(), client, opts.Namespace, workload)
+		if err != nil {
+			return err
+		}
+
+		out := stdout()
+		color := colorEnabled()
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		log.Info().Str("workload", workload.String()).Str("namespace", opts.Namespace).Str("selector", opts.Selector.String()).Bool("follow", opts.Follow).Msg("Streaming logs")
+		return kube.StreamLogs(ctx, client, opts, func(line kube.LogLine) {
+			if line.Err != nil {
+				log.Warn().Err(line.Err).Str("pod", line.Pod).Str("container", line.Container).Msg("Log stream failed")
+				return
+			}
+			fmt.Fprintf(out, "%s %s\n", logPrefix(line.Pod, line.Container, color), line.Text)
+		})
+	},
+}
+
+// logOptionsFromFlags builds the stream options shared by the log commands.
+func logOptionsFromFlags(cmd *cobra.Command) (kube.LogOptions, error) {
+	var opts kube.LogOptions
+	opts.Container, _ = cmd.Flags().GetString("container")
+	opts.Since, _ = cmd.Flags().GetDuration("since")
+	opts.Tail, _ = cmd.Flags().GetInt64("tail")
+	if cmd.Flags().Lookup("follow") != nil {
+		opts.Follow, _ = cmd.Flags().GetBool("follow")
+	}
+	if opts.Since < 0 {
+		return opts, errs.ValidationFailed("--since must not be negative")
+	}
+
+	if pattern, _ := cmd.Flags().GetString("grep"); pattern != "" {
+		filter, err := regexp.Compile(pattern)
+		if err != nil {
+			return opts, errs.Wrap(errs.CodeValidationFailed, err, "invalid --grep pattern")
+		}
+		opts.Filter = filter
+	}
+	return opts, nil
+}
+
+// logPrefix renders "[pod/container]", colored by pod when color is set.
+func logPrefix(pod, container string, color bool) string {
+	prefix := "[" + pod + "/" + container + "]"
+	if !color {
+		return prefix
+	}
+	h := fnv.New32a()
+	h.Write([]byte(pod))
+	return ansi(logColors[h.Sum32()%uint32(len(logColors))], prefix)
+}
+
+// addLogStreamFlags registers the pod and line selection flags shared by the log commands.
+func addLogStreamFlags(cmd *cobra.Command, since time.Duration) {
+	cmd.Flags().StringP("container", "c", "", "Only stream containers with this name")
+	cmd.Flags().Duration("since", since, "Only return logs newer than this duration, e.g. 10m")
+	cmd.Flags().Int64("tail", -1, "Lines of recent log to show per container, -1 shows all")
+	cmd.Flags().String("grep", "", "Only print lines matching this regular expression")
+}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+
+	addLogStreamFlags(logsCmd, 0)
+	logsCmd.Flags().BoolP("follow", "f", false, "Keep streaming and pick up new pods and restarted containers")
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/manifest"
+)
+
+var mergeCmd = &cobra.Command{
+	Use:     "merge",
+	GroupID: groupGenerate,
+	Short:   "Layer override manifests onto a base manifest",
+	Long: `Layer override manifests onto a base manifest locally, no cluster needed.
+
+The first -f is the base, every following -f is applied on top in order.
+Objects are matched by kind, namespace and name. Built-in kinds are merged
+like kubectl patch --type strategic, so containers, env vars and ports merge by
+name; custom resources use JSON merge patch, which replaces lists. Objects
+only present in an override are added, and an override object with
+"$patch: delete" removes the matching object.`,
+	Example: `  k8s-controller-cli merge -f base.yaml -f prod.yaml
+  k8s-controller-cli merge -f base.yaml -f prod.yaml -f prod-eu.yaml -o rendered.yaml`,
+	Args: strictArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filenames, _ := cmd.Flags().GetStringArray("filename")
+		output, _ := cmd.Flags().GetString("output")
+		if len(filenames) < 2 {
+			return errs.ValidationFailed("please provide a base and at least one override with -f")
+		}
+
+		manifests := make([][]byte, 0, len(filenames))
+		stdinUsed := false
+		for _, filename := range filenames {
+			if filename == "-" {
+				if stdinUsed {
+					return errs.ValidationFailed("stdin can only be used once")
+				}
+				stdinUsed = true
+			}
+			data, err := readManifestFile(filename)
+			if err != nil {
+				return err
+			}
+			manifests = append(manifests, data)
+		}
+
+		merged, err := manifest.Merge(manifests[0], manifests[1:]...)
+		if err != nil {
+			return err
+		}
+		log.Info().Str("base", filenames[0]).Strs("overrides", filenames[1:]).Msg("Manifests merged")
+		return writeOutput(output, merged)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mergeCmd)
+
+	mergeCmd.Flags().StringArrayP("filename", "f", nil, "Base manifest followed by overrides, repeatable, - for stdin")
+	mergeCmd.Flags().StringP("output", "o", "", "Output file, defaults to stdout")
+	_ = mergeCmd.MarkFlagRequired("filename")
+}
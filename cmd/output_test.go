@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/messages"
+)
+
+func TestPrintHonorsQuiet(t *testing.T) {
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	defer func() {
+		rootCmd.SetOut(nil)
+		quiet = false
+	}()
+
+	quiet = true
+	printInfo(messages.NodeCountDefined, 3)
+	printResult(messages.ClusterName, "test-cluster")
+
+	expected := "Cluster name: test-cluster\n"
+	if buf.String() != expected {
+		t.Errorf("expected output %q, got %q", expected, buf.String())
+	}
+}
+
+func TestColorizeOnlyOnTerminals(t *testing.T) {
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	defer rootCmd.SetOut(nil)
+
+	if got := colorize(colorRed, "failed"); got != "failed" {
+		t.Errorf("expected no escape codes when output isn't a terminal, got %q", got)
+	}
+	if got := ansi(colorRed, "failed"); got != "\x1b[31mfailed\x1b[0m" {
+		t.Errorf("unexpected escape codes %q", got)
+	}
+}
+
+func TestTableCell(t *testing.T) {
+	got := tableCell("dial tcp: timeout\n\tretrying\r\nconnection refused")
+	if got != "dial tcp: timeout retrying connection refused" {
+		t.Errorf("unexpected cell %q", got)
+	}
+	if got := tableCell("plain"); got != "plain" {
+		t.Errorf("unexpected cell %q", got)
+	}
+}
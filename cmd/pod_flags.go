@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/builder"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// addPodFlags registers the create-pod options that go beyond the image and port.
+func addPodFlags(flags *pflag.FlagSet) {
+	flags.StringArray("secret", nil, "Mount a Secret read-only, as name or name:path (defaults to /etc/<name>), can be repeated")
+	flags.StringArray("secret-env", nil, "Set an env var from a Secret key, as VAR=secret:key, can be repeated")
+	flags.StringArray("node-selector", nil, "Only schedule onto nodes with the label key=value, can be repeated")
+	flags.StringArray("toleration", nil, "Tolerate a taint, as key=value:Effect, key:Effect or key (any value), can be repeated")
+	flags.String("affinity-file", "", "YAML file with the pod's affinity (nodeAffinity, podAffinity, podAntiAffinity)")
+	flags.Int64("run-as-user", 0, "Run the pod's processes as this user ID")
+	flags.Bool("run-as-non-root", false, "Refuse to start containers running as root")
+	flags.Bool("read-only-root-fs", false, "Mount the container's root filesystem read-only")
+	flags.StringSlice("drop-capabilities", nil, "Linux capabilities to drop from the container, e.g. ALL")
+}
+
+// applyPodFlags passes the options registered by addPodFlags to b.
+func applyPodFlags(flags *pflag.FlagSet, b *builder.PodBuilder) error {
+	secrets, _ := flags.GetStringArray("secret")
+	for _, value := range secrets {
+		name, mountPath, found := strings.Cut(value, ":")
+		if name == "" || (found && mountPath == "") {
+			return errs.ValidationFailed("invalid --secret %q, expected name or name:path", value)
+		}
+		if !found {
+			b.SecretVolume(name)
+			continue
+		}
+		b.SecretVolumeAt(name, mountPath)
+	}
+
+	secretEnvs, _ := flags.GetStringArray("secret-env")
+	for _, value := range secretEnvs {
+		name, ref, _ := strings.Cut(value, "=")
+		secret, key, _ := strings.Cut(ref, ":")
+		if name == "" || secret == "" || key == "" {
+			return errs.ValidationFailed("invalid --secret-env %q, expected VAR=secret:key", value)
+		}
+		b.SecretEnv(name, secret, key)
+	}
+
+	selectors, _ := flags.GetStringArray("node-selector")
+	for _, value := range selectors {
+		key, label, found := strings.Cut(value, "=")
+		if key == "" || !found {
+			return errs.ValidationFailed("invalid --node-selector %q, expected key=value", value)
+		}
+		b.NodeSelector(key, label)
+	}
+
+	tolerations, _ := flags.GetStringArray("toleration")
+	for _, value := range tolerations {
+		toleration, err := parseToleration(value)
+		if err != nil {
+			return err
+		}
+		b.Toleration(toleration)
+	}
+
+	if path, _ := flags.GetString("affinity-file"); path != "" {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			return errs.Wrap(errs.CodeNotFound, err, "reading affinity")
+		}
+		if err != nil {
+			return errs.Wrap(errs.CodeUnknown, err, "reading affinity")
+		}
+		affinity := &corev1.Affinity{}
+		if err := yaml.UnmarshalStrict(data, affinity); err != nil {
+			return errs.Wrap(errs.CodeValidationFailed, err, "decoding affinity %s", path)
+		}
+		b.Affinity(affinity)
+	}
+
+	if flags.Changed("run-as-user") {
+		uid, _ := flags.GetInt64("run-as-user")
+		b.RunAsUser(uid)
+	}
+	if nonRoot, _ := flags.GetBool("run-as-non-root"); nonRoot {
+		b.RunAsNonRoot()
+	}
+	if readOnly, _ := flags.GetBool("read-only-root-fs"); readOnly {
+		b.ReadOnlyRootFilesystem()
+	}
+	if capabilities, _ := flags.GetStringSlice("drop-capabilities"); len(capabilities) > 0 {
+		b.DropCapabilities(capabilities...)
+	}
+	return nil
+}
+
+// parseToleration parses a --toleration value like the taints of kubectl taint:
+// key=value:Effect tolerates that taint, a key without value tolerates any value
+// and without effect every effect.
+func parseToleration(value string) (corev1.Toleration, error) {
+	toleration := corev1.Toleration{Operator: corev1.TolerationOpExists}
+	rest := value
+	if i := strings.LastIndex(rest, ":"); i >= 0 {
+		toleration.Effect = corev1.TaintEffect(rest[i+1:])
+		rest = rest[:i]
+	}
+	if key, v, found := strings.Cut(rest, "="); found {
+		toleration.Key, toleration.Value = key, v
+		toleration.Operator = corev1.TolerationOpEqual
+	} else {
+		toleration.Key = rest
+	}
+	if toleration.Key == "" {
+		return toleration, errs.ValidationFailed("invalid --toleration %q, expected key=value:Effect", value)
+	}
+	return toleration, nil
+}
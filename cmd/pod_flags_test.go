@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/builder"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// podFlags returns a flag set with the create-pod options parsed from args.
+func podFlags(t *testing.T, args ...string) *pflag.FlagSet {
+	t.Helper()
+	flags := pflag.NewFlagSet("create-pod", pflag.ContinueOnError)
+	addPodFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return flags
+}
+
+func TestApplyPodFlagsSecrets(t *testing.T) {
+	b := builder.NewPod("web").Image("nginx")
+	if err := applyPodFlags(podFlags(t, "--secret", "web-tls", "--secret", "creds:/creds", "--secret-env", "DB_PASSWORD=db:password"), b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pod, err := b.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mounts := pod.Spec.Containers[0].VolumeMounts
+	if len(mounts) != 2 || mounts[0].MountPath != "/etc/web-tls" || mounts[1].MountPath != "/creds" {
+		t.Errorf("unexpected mounts %+v", mounts)
+	}
+	if ref := pod.Spec.Containers[0].Env[0].ValueFrom.SecretKeyRef; ref.Name != "db" || ref.Key != "password" {
+		t.Errorf("unexpected secret ref %+v", ref)
+	}
+
+	for _, args := range [][]string{
+		{"--secret", "web:"},
+		{"--secret-env", "DB_PASSWORD=db"},
+		{"--secret-env", "=db:password"},
+	} {
+		if err := applyPodFlags(podFlags(t, args...), builder.NewPod("web")); !errs.IsValidationFailed(err) {
+			t.Errorf("%v: expected validation error, got %v", args, err)
+		}
+	}
+}
+
+func TestParseToleration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected corev1.Toleration
+	}{
+		{"dedicated=web:NoSchedule", corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "web", Effect: corev1.TaintEffectNoSchedule}},
+		{"gpu:NoExecute", corev1.Toleration{Key: "gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute}},
+		{"gpu", corev1.Toleration{Key: "gpu", Operator: corev1.TolerationOpExists}},
+	}
+	for _, tt := range tests {
+		got, err := parseToleration(tt.input)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", tt.input, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("expected %+v for %q, got %+v", tt.expected, tt.input, got)
+		}
+	}
+	if _, err := parseToleration("=web:NoSchedule"); !errs.IsValidationFailed(err) {
+		t.Errorf("expected validation error for a toleration without key, got %v", err)
+	}
+}
+
+func TestApplyPodFlagsScheduling(t *testing.T) {
+	affinityFile := filepath.Join(t.TempDir(), "affinity.yaml")
+	affinity := []byte(`nodeAffinity:
+  requiredDuringSchedulingIgnoredDuringExecution:
+    nodeSelectorTerms:
+    - matchExpressions:
+      - key: topology.kubernetes.io/zone
+        operator: In
+        values: [eu-west-1a]
+`)
+	if err := os.WriteFile(affinityFile, affinity, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	b := builder.NewPod("web").Image("nginx")
+	flags := podFlags(t, "--node-selector", "disktype=ssd", "--toleration", "dedicated=web:NoSchedule", "--affinity-file", affinityFile)
+	if err := applyPodFlags(flags, b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pod, err := b.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pod.Spec.NodeSelector["disktype"] != "ssd" || pod.Spec.Tolerations[0].Key != "dedicated" {
+		t.Errorf("unexpected scheduling constraints %+v %+v", pod.Spec.NodeSelector, pod.Spec.Tolerations)
+	}
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil {
+		t.Errorf("expected the node affinity from the file, got %+v", pod.Spec.Affinity)
+	}
+
+	if err := applyPodFlags(podFlags(t, "--node-selector", "disktype"), builder.NewPod("web")); !errs.IsValidationFailed(err) {
+		t.Errorf("expected validation error for a selector without value, got %v", err)
+	}
+	if err := os.WriteFile(affinityFile, []byte("nodeAfinity: {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyPodFlags(podFlags(t, "--affinity-file", affinityFile), builder.NewPod("web")); !errs.IsValidationFailed(err) {
+		t.Errorf("expected validation error for an unknown affinity field, got %v", err)
+	}
+}
+
+func TestApplyPodFlagsSecurityContext(t *testing.T) {
+	b := builder.NewPod("web").Image("nginx")
+	flags := podFlags(t, "--run-as-user", "1000", "--run-as-non-root", "--read-only-root-fs", "--drop-capabilities", "ALL")
+	if err := applyPodFlags(flags, b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pod, err := b.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if podSecurity := pod.Spec.SecurityContext; *podSecurity.RunAsUser != 1000 || !*podSecurity.RunAsNonRoot {
+		t.Errorf("unexpected pod security context %+v", podSecurity)
+	}
+	if security := pod.Spec.Containers[0].SecurityContext; !*security.ReadOnlyRootFilesystem || security.Capabilities.Drop[0] != "ALL" {
+		t.Errorf("unexpected container security context %+v", security)
+	}
+
+	b = builder.NewPod("web").Image("nginx")
+	if err := applyPodFlags(podFlags(t), b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pod, _ := b.Build(); pod.Spec.SecurityContext != nil || pod.Spec.Containers[0].SecurityContext != nil {
+		t.Errorf("expected no security context without flags, got %+v", pod.Spec)
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/kube"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/messages"
+)
+
+var rbacCmd = &cobra.Command{
+	Use:     "rbac",
+	GroupID: groupCluster,
+	Short:   "Inspect RBAC permissions",
+}
+
+var rbacWhoCanCmd = &cobra.Command{
+	Use:   "who-can <verb> <resource>[/subresource] [name]",
+	Short: "List the subjects allowed to perform an action",
+	Long: `List the subjects allowed to perform an action.
+
+Roles, ClusterRoles and their bindings are evaluated locally. Only RBAC is
+considered, permissions granted by other authorizers are not reported.
+Resources accept the same short names as kubectl (deploy, svc, cm).`,
+	Example: `  k8s-controller-cli rbac who-can get deployments -n payments
+  k8s-controller-cli rbac who-can update deploy/scale web -n payments
+  k8s-controller-cli rbac who-can list nodes`,
+	Args: strictArgs(cobra.RangeArgs(2, 3)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		if output != "table" && output != "json" {
+			return errs.ValidationFailed("unsupported output format %q, use table or json", output)
+		}
+
+		client, err := kubeOptions.Clientset()
+		if err != nil {
+			return err
+		}
+
+		resource, subresource, _ := strings.Cut(args[1], "/")
+		gvr, namespaced, err := kube.ResolveResource(client.Discovery(), resource)
+		if err != nil {
+			return err
+		}
+
+		req := kube.AccessRequest{
+			Verb:        args[0],
+			Group:       gvr.Group,
+			Resource:    gvr.Resource,
+			Subresource: subresource,
+		}
+		if len(args) == 3 {
+			req.Name = args[2]
+		}
+		if namespaced {
+			req.Namespace = kubeOptions.ResolveNamespace()
+		}
+
+		log.Info().Str("verb", req.Verb).Str("resource", gvr.GroupResource().String()).Str("namespace", req.Namespace).Msg("Evaluating RBAC bindings")
+		grants, err := kube.WhoCan(cmd.Context(), client, req)
+		if err != nil {
+			return err
+		}
+
+		if output == "json" {
+			encoder := json.NewEncoder(stdout())
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(grants)
+		}
+
+		w := tabwriter.NewWriter(stdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, messages.Get(messages.RBACHeader))
+		for _, g := range grants {
+			subject := g.SubjectName
+			if g.SubjectNamespace != "" {
+				subject = g.SubjectNamespace + "/" + g.SubjectName
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s/%s\t%s/%s\n", g.SubjectKind, subject, g.BindingKind, g.BindingName, g.RoleKind, g.RoleName)
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rbacCmd)
+	rbacCmd.AddCommand(rbacWhoCanCmd)
+
+	rbacWhoCanCmd.Flags().StringP("output", "o", "table", "Output format: table or json")
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/registry"
+)
+
+var registryCmd = &cobra.Command{
+	Use:     "registry",
+	GroupID: groupDebug,
+	Short:   "Query container registries",
+}
+
+var registryTagsCmd = &cobra.Command{
+	Use:   "tags <image>",
+	Short: "List the tags of an image repository",
+	Long: `List the tags of an image repository.
+
+Credentials are read from the docker config (~/.docker/config.json and
+credential helpers). With --constraint only tags that are semantic versions
+satisfying the constraint are printed, sorted from oldest to newest.`,
+	Example: `  k8s-controller-cli registry tags nginx
+  k8s-controller-cli registry tags ghcr.io/org/app --constraint ">=1.2, <2"
+  k8s-controller-cli registry tags nginx --constraint "~1.27" --latest`,
+	Args: strictArgs(cobra.ExactArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		constraint, _ := cmd.Flags().GetString("constraint")
+		latest, _ := cmd.Flags().GetBool("latest")
+		if latest && constraint == "" {
+			return errs.ValidationFailed("--latest requires --constraint")
+		}
+
+		log.Info().Str("image", args[0]).Msg("Listing image tags")
+		tags, err := registry.ListTags(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+
+		if constraint != "" {
+			tags, err = registry.FilterTags(tags, constraint)
+			if err != nil {
+				return err
+			}
+			if len(tags) == 0 {
+				return errs.NotFound("no tags of %s satisfy %q", args[0], constraint)
+			}
+		}
+		if latest {
+			tags = tags[len(tags)-1:]
+		}
+
+		for _, tag := range tags {
+			fmt.Fprintln(stdout(), tag)
+		}
+		log.Info().Int("tags_count", len(tags)).Msg("Image tags listed successfully")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(registryCmd)
+	registryCmd.AddCommand(registryTagsCmd)
+
+	registryTagsCmd.Flags().String("constraint", "", "Semver constraint tags must satisfy, e.g. \">=1.2, <2\"")
+	registryTagsCmd.Flags().Bool("latest", false, "Only print the newest tag satisfying --constraint")
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/kube"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/messages"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var restartCmd = &cobra.Command{
+	Use:     "restart deployment/<name>",
+	GroupID: groupCluster,
+	Short:   "Perform a rolling restart of a deployment",
+	Long: `Perform a rolling restart of a deployment by stamping its pod template, like
+kubectl rollout restart.
+
+--max-unavailable updates the deployment's rolling update budget before the
+restart and stays in effect afterwards. With --wait, pod changes are printed
+as the rollout progresses and the command fails with diagnostics when a new
+pod crashes or can't start.`,
+	Example: `  k8s-controller-cli restart deployment/web -n payments
+  k8s-controller-cli restart deploy/web --max-unavailable 1 --wait
+  k8s-controller-cli restart deploy/web --max-unavailable 25% --wait --timeout 10m`,
+	Args: strictArgs(cobra.ExactArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workload, err := kube.ParseWorkload(args[0])
+		if err != nil {
+			return err
+		}
+		if workload.Kind != "deployment" {
+			return errs.ValidationFailed("restart only supports deployments, got %s", workload.Kind)
+		}
+		wait, _ := cmd.Flags().GetBool("wait")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+
+		var maxUnavailable *intstr.IntOrString
+		if cmd.Flags().Changed("max-unavailable") {
+			value, _ := cmd.Flags().GetString("max-unavailable")
+			parsed, err := parseMaxUnavailable(value)
+			if err != nil {
+				return err
+			}
+			maxUnavailable = &parsed
+		}
+
+		client, err := kubeOptions.Clientset()
+		if err != nil {
+			return err
+		}
+		namespace := kubeOptions.ResolveNamespace()
+
+		started := time.Now()
+		if _, err := kube.RestartDeployment(cmd.Context(), client, namespace, workload.Name, maxUnavailable, started); err != nil {
+			return err
+		}
+		log.Info().Str("deployment", workload.Name).Str("namespace", namespace).Msg("Restart triggered")
+		if !wait {
+			printResult(messages.RestartTriggered, workload)
+			return nil
+		}
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel()
+		watcher := &kube.RolloutWatcher{
+			Client:    client,
+			Namespace: namespace,
+			Name:      workload.Name,
+			OnStatus: func(status kube.RolloutStatus) {
+				fmt.Fprintln(stdout(), colorize(rolloutStatusColor(status), messages.Get(messages.RolloutStatus, workload, status)))
+			},
+			OnPod: func(pod kube.PodState) {
+				fmt.Fprintln(stdout(), colorize(podStateColor(pod), messages.Get(messages.RolloutPod, pod)))
+			},
+		}
+		if err := watcher.Wait(ctx); err != nil {
+			return err
+		}
+		printResult(messages.RestartCompleted, workload, formatAge(time.Since(started)))
+		return nil
+	},
+}
+
+// rolloutStatusColor highlights a rollout with no available replicas.
+func rolloutStatusColor(status kube.RolloutStatus) string {
+	if status.Desired > 0 && status.Available == 0 {
+		return colorRed
+	}
+	return ""
+}
+
+// podStateColor highlights failing pods in red and pods that aren't ready yet in yellow.
+func podStateColor(pod kube.PodState) string {
+	switch {
+	case pod.Failing():
+		return colorRed
+	case !pod.Ready && pod.Phase != "Terminating" && pod.Phase != "Deleted":
+		return colorYellow
+	}
+	return ""
+}
+
+// parseMaxUnavailable accepts a pod count ("1") or a percentage ("25%").
+func parseMaxUnavailable(value string) (intstr.IntOrString, error) {
+	parsed := intstr.Parse(value)
+	scaled, err := intstr.GetScaledValueFromIntOrPercent(&parsed, 100, false)
+	if err != nil {
+		return parsed, errs.Wrap(errs.CodeValidationFailed, err, "invalid --max-unavailable %q", value)
+	}
+	if scaled < 0 {
+		return parsed, errs.ValidationFailed("--max-unavailable must not be negative")
+	}
+	return parsed, nil
+}
+
+func init() {
+	rootCmd.AddCommand(restartCmd)
+
+	restartCmd.Flags().String("max-unavailable", "", "Pods that may be unavailable during the restart, a number or a percentage")
+	restartCmd.Flags().Bool("wait", false, "Wait for the rollout to finish, printing pod changes")
+	restartCmd.Flags().Duration("timeout", 5*time.Minute, "How long to wait for the rollout with --wait")
+}
@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/kube"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/manifest"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/messages"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/revision"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// Manifest sync states reported by revision check.
+const (
+	syncInSync      = "InSync"
+	syncDrifted     = "Drifted"
+	syncUnannotated = "NotAnnotated"
+	syncMissing     = "Missing"
+)
+
+var revisionCmd = &cobra.Command{
+	Use:     "revision",
+	GroupID: groupGenerate,
+	Short:   "Compute template and input hashes to compare manifests with running workloads",
+}
+
+var revisionTemplateHashCmd = &cobra.Command{
+	Use:   "template-hash [deployment/<name>]",
+	Short: "Compute the pod-template-hash of a deployment",
+	Long: `Compute the pod-template-hash of a deployment the way the Deployment
+controller does.
+
+For a live deployment the ReplicaSet of the current revision and the hash it
+is labeled with are reported too.
+With -f the hash is computed from a manifest; it only matches the cluster when
+the template spells out every field the API server defaults.`,
+	Example: `  k8s-controller-cli revision template-hash deployment/web -n payments
+  k8s-controller-cli revision template-hash -f deployment.yaml`,
+	Args: strictArgs(cobra.MaximumNArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filename, _ := cmd.Flags().GetString("filename")
+		if (filename == "") == (len(args) == 0) {
+			return errs.ValidationFailed("provide either a deployment or a manifest with -f")
+		}
+		if filename != "" {
+			return printManifestTemplateHashes(filename)
+		}
+
+		workload, err := kube.ParseWorkload(args[0])
+		if err != nil {
+			return err
+		}
+		if workload.Kind != "deployment" {
+			return errs.ValidationFailed("template-hash only supports deployments, got %s", workload.Kind)
+		}
+		client, err := kubeOptions.Clientset()
+		if err != nil {
+			return err
+		}
+		result, err := kube.DeploymentTemplateRevision(cmd.Context(), client, kubeOptions.ResolveNamespace(), workload.Name)
+		if err != nil {
+			return err
+		}
+
+		printResult(messages.RevisionHash, result.Hash)
+		if result.ReplicaSet == "" {
+			log.Warn().Str("deployment", workload.Name).Msg("The controller hasn't created the ReplicaSet of the current revision yet")
+			return nil
+		}
+		printResult(messages.RevisionReplicaSet, result.ReplicaSet)
+		printResult(messages.RevisionLiveHash, result.ReplicaSetHash)
+		printResult(messages.RevisionNumber, result.Revision)
+		if result.ReplicaSetHash != result.Hash {
+			log.Warn().Str("deployment", workload.Name).Msg("The locally computed hash differs from the hash of the current ReplicaSet")
+		}
+		return nil
+	},
+}
+
+var revisionAnnotateCmd = &cobra.Command{
+	Use:   "annotate",
+	Short: "Record the input hash of every object of a manifest as an annotation",
+	Long: `Record the SHA-256 of every object of a manifest in the ` + revision.InputHashAnnotation + `
+annotation. Once applied, revision check tells whether the running objects were
+created from the same manifest. Annotate before signing, the signature
+annotation is ignored by the hash but the hash annotation is signed.`,
+	Example: `  k8s-controller-cli revision annotate -f app.yaml -o app.annotated.yaml`,
+	Args:    strictArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filename, _ := cmd.Flags().GetString("filename")
+		output, _ := cmd.Flags().GetString("output")
+
+		data, err := readManifestFile(filename)
+		if err != nil {
+			return err
+		}
+		annotated, err := revision.AnnotateManifest(data)
+		if err != nil {
+			return err
+		}
+		return writeOutput(output, annotated)
+	},
+}
+
+var revisionCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Compare the input hashes of a manifest with the running objects",
+	Long: `Compare the input hash of every object of a manifest with the
+` + revision.InputHashAnnotation + ` annotation of the matching object in the cluster.
+The command fails when an object is missing or was created from different input.`,
+	Example: `  k8s-controller-cli revision check -f app.yaml -n payments`,
+	Args:    strictArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filename, _ := cmd.Flags().GetString("filename")
+		data, err := readManifestFile(filename)
+		if err != nil {
+			return err
+		}
+		docs, err := manifest.Documents(data)
+		if err != nil {
+			return err
+		}
+
+		clientset, err := kubeOptions.Clientset()
+		if err != nil {
+			return err
+		}
+		client, err := kubeOptions.DynamicClient()
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(stdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, messages.Get(messages.RevisionCheckHeader))
+		differ := 0
+		for i, doc := range docs {
+			obj := &unstructured.Unstructured{}
+			if err := yaml.Unmarshal(doc, &obj.Object); err != nil {
+				return errs.Wrap(errs.CodeValidationFailed, err, "decoding document %d", i)
+			}
+			expected, err := revision.InputHash(obj.Object)
+			if err != nil {
+				return err
+			}
+
+			gvr, namespaced, err := kube.ResolveKind(clientset.Discovery(), obj.GroupVersionKind())
+			if err != nil {
+				return err
+			}
+			namespace := ""
+			if namespaced {
+				namespace = obj.GetNamespace()
+				if namespace == "" {
+					namespace = kubeOptions.ResolveNamespace()
+				}
+			}
+
+			status := syncInSync
+			live, err := client.Resource(gvr).Namespace(namespace).Get(cmd.Context(), obj.GetName(), metav1.GetOptions{})
+			switch {
+			case apierrors.IsNotFound(err):
+				status = syncMissing
+			case err != nil:
+				return errs.FromAPI(err, "getting %s %s", obj.GetKind(), obj.GetName())
+			default:
+				actual, ok := live.GetAnnotations()[revision.InputHashAnnotation]
+				switch {
+				case !ok:
+					status = syncUnannotated
+				case actual != expected:
+					status = syncDrifted
+				}
+			}
+			if status == syncMissing || status == syncDrifted {
+				differ++
+			}
+
+			name := obj.GetName()
+			if namespace != "" {
+				name = namespace + "/" + name
+			}
+			fmt.Fprintf(w, "%s %s\t%s\n", obj.GetKind(), name, status)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		if differ > 0 {
+			return errs.Conflict("%d of %d objects are missing or differ from the manifest", differ, len(docs))
+		}
+		return nil
+	},
+}
+
+// printManifestTemplateHashes prints the pod-template-hash of every Deployment in a manifest.
+func printManifestTemplateHashes(filename string) error {
+	data, err := readManifestFile(filename)
+	if err != nil {
+		return err
+	}
+	docs, err := manifest.Documents(data)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(stdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, messages.Get(messages.RevisionHashHeader))
+	found := 0
+	for i, doc := range docs {
+		var deployment appsv1.Deployment
+		if err := yaml.Unmarshal(doc, &deployment); err != nil {
+			return errs.Wrap(errs.CodeValidationFailed, err, "decoding document %d", i)
+		}
+		if deployment.APIVersion != "apps/v1" || deployment.Kind != "Deployment" {
+			continue
+		}
+		found++
+		fmt.Fprintf(w, "%s\t%s\n", deployment.Name, revision.PodTemplateHash(&deployment.Spec.Template, deployment.Status.CollisionCount))
+	}
+	if found == 0 {
+		return errs.NotFound("no apps/v1 Deployment in %s", filename)
+	}
+	return w.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(revisionCmd)
+	revisionCmd.AddCommand(revisionTemplateHashCmd, revisionAnnotateCmd, revisionCheckCmd)
+
+	revisionTemplateHashCmd.Flags().StringP("filename", "f", "", "Manifest with Deployments to hash instead of a live deployment, - for stdin")
+
+	revisionAnnotateCmd.Flags().StringP("filename", "f", "", "Manifest to annotate, - for stdin")
+	revisionAnnotateCmd.Flags().StringP("output", "o", "", "Output file, defaults to stdout")
+	_ = revisionAnnotateCmd.MarkFlagRequired("filename")
+
+	revisionCheckCmd.Flags().StringP("filename", "f", "", "Annotated manifest to compare, - for stdin")
+	_ = revisionCheckCmd.MarkFlagRequired("filename")
+}
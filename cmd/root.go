@@ -5,10 +5,14 @@ package cmd
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -24,20 +28,69 @@ to quickly create a Cobra application.`,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Flags and arguments were validated before this hook, so later errors
+		// aren't usage mistakes and shouldn't print usage
+		cmd.SilenceUsage = true
+
+		if err := configureOutput(); err != nil {
+			return err
+		}
+		logBuildInfo()
+		return configureRuntime(cmd)
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// The process exit code is derived from the error type, see errs.ExitCode.
 func Execute() {
-	err := rootCmd.Execute()
+	start := time.Now()
+	cmd, err := execute()
+	recordTelemetry(cmd, err, time.Since(start))
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to execute command")
-		os.Exit(1)
+		log.Error().Err(err).Str("code", string(errs.CodeOf(err))).Msg("Failed to execute command")
+		os.Exit(errs.ExitCode(err))
+	}
+}
+
+// execute runs the root command and returns the command that ran with its error.
+func execute() (*cobra.Command, error) {
+	cmd, err := rootCmd.ExecuteC()
+	// Cobra reports unknown subcommands of the root command before any of our
+	// validation runs, they're usage mistakes like unknown flags
+	if err != nil && strings.HasPrefix(err.Error(), "unknown command ") {
+		err = errs.Wrap(errs.CodeValidationFailed, err, "")
+	}
+	return cmd, err
+}
+
+// stateDir returns the directory holding the CLI's local state, falling back to the working directory.
+func stateDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".k8s-controller-tutorial"
+	}
+	return filepath.Join(home, ".k8s-controller-tutorial")
+}
+
+// strictArgs reports positional argument errors as validation failures so they map to the usage exit code.
+func strictArgs(validate cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		return errs.Wrap(errs.CodeValidationFailed, validate(cmd, args), "")
 	}
 }
 
 func init() {
-	// Configure zerolog for pretty console output
+	// Execute logs the error with its code, cobra printing it too would repeat it
+	rootCmd.SilenceErrors = true
+
+	// Unknown flags and bad flag values are validation failures as well
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return errs.Wrap(errs.CodeValidationFailed, err, "")
+	})
+
+	// Configure zerolog for pretty console output on stderr, keeping stdout for command output
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
 	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
 
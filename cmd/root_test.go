@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+)
+
+// executeRoot runs the root command with args and returns everything written to its output.
+func executeRoot(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var buf bytes.Buffer
+	rootCmd.SetArgs(args)
+	rootCmd.SetOut(&buf)
+	rootCmd.SetErr(&buf)
+	logger := log.Logger
+	t.Cleanup(func() {
+		log.Logger = logger
+		rootCmd.SetArgs(nil)
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		resetSilenceUsage(rootCmd)
+	})
+
+	_, err := execute()
+	return buf.String(), err
+}
+
+// resetSilenceUsage undoes the SilenceUsage the pre-run hook sets, so a later
+// execution in the same test binary prints usage again.
+func resetSilenceUsage(cmd *cobra.Command) {
+	cmd.SilenceUsage = false
+	for _, child := range cmd.Commands() {
+		resetSilenceUsage(child)
+	}
+}
+
+func TestUsageOnlyForUsageErrors(t *testing.T) {
+	output, err := executeRoot(t, "revision", "annotate", "extra")
+	if !errs.IsValidationFailed(err) {
+		t.Errorf("expected validation error, got %v", err)
+	}
+	if !strings.Contains(output, "Usage:") {
+		t.Errorf("expected usage for an argument error, got %q", output)
+	}
+
+	output, err = executeRoot(t, "revision", "annotate", "-f", filepath.Join(t.TempDir(), "missing.yaml"))
+	if err == nil {
+		t.Fatal("expected an error for a missing manifest")
+	}
+	if strings.Contains(output, "Usage:") {
+		t.Errorf("expected no usage once arguments are valid, got %q", output)
+	}
+}
+
+func TestUnknownCommandIsUsageError(t *testing.T) {
+	_, err := executeRoot(t, "no-such-command")
+	if !errs.IsValidationFailed(err) {
+		t.Errorf("expected validation error, got %v", err)
+	}
+}
+
+func TestAddUserRequiresUsername(t *testing.T) {
+	_, err := executeRoot(t, "demo", "add-user")
+	if !errs.IsValidationFailed(err) {
+		t.Errorf("expected validation error, got %v", err)
+	}
+}
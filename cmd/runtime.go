@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	"go.uber.org/automaxprocs/maxprocs"
+)
+
+var (
+	gcPercent   int
+	memoryLimit string
+)
+
+// byteSuffixes maps the supported size suffixes to their multipliers.
+var byteSuffixes = []struct {
+	suffix     string
+	multiplier int64
+}{
+	{"Ki", 1 << 10},
+	{"Mi", 1 << 20},
+	{"Gi", 1 << 30},
+	{"Ti", 1 << 40},
+	{"K", 1000},
+	{"M", 1000 * 1000},
+	{"G", 1000 * 1000 * 1000},
+	{"T", 1000 * 1000 * 1000 * 1000},
+}
+
+// parseByteSize converts sizes like "512Mi", "2G" or "1048576" into bytes.
+func parseByteSize(size string) (int64, error) {
+	value := strings.TrimSpace(size)
+	multiplier := int64(1)
+	for _, s := range byteSuffixes {
+		if strings.HasSuffix(value, s.suffix) {
+			value = strings.TrimSuffix(value, s.suffix)
+			multiplier = s.multiplier
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", size)
+	}
+	if n > math.MaxInt64/multiplier {
+		return 0, fmt.Errorf("size %q is too large", size)
+	}
+	return n * multiplier, nil
+}
+
+// configureRuntime applies GC tuning flags and aligns GOMAXPROCS with the container
+// CPU quota. The resulting settings are logged at debug level, run with -v to see them.
+func configureRuntime(cmd *cobra.Command) error {
+	if _, err := maxprocs.Set(maxprocs.Logger(func(format string, args ...interface{}) {
+		log.Debug().Msgf(format, args...)
+	})); err != nil {
+		log.Warn().Err(err).Msg("Failed to set GOMAXPROCS from cgroup CPU quota")
+	}
+
+	event := log.Debug()
+	if cmd.Flags().Changed("gc-percent") {
+		debug.SetGCPercent(gcPercent)
+		event = event.Int("gc_percent", gcPercent)
+	}
+
+	if memoryLimit != "" {
+		limit, err := parseByteSize(memoryLimit)
+		if err != nil {
+			return errs.Wrap(errs.CodeValidationFailed, err, "--memory-limit")
+		}
+		debug.SetMemoryLimit(limit)
+	}
+
+	//a negative limit only reads the current setting
+	event.
+		Int("gomaxprocs", runtime.GOMAXPROCS(0)).
+		Int("num_cpu", runtime.NumCPU()).
+		Int64("memory_limit_bytes", debug.SetMemoryLimit(-1)).
+		Msg("Runtime configured")
+	return nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().IntVar(&gcPercent, "gc-percent", 100, "Garbage collection target percentage (see GOGC), negative disables GC")
+	rootCmd.PersistentFlags().StringVar(&memoryLimit, "memory-limit", "", "Soft memory limit for the Go runtime, e.g. 512Mi or 1G (see GOMEMLIMIT)")
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import "testing"
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+		wantErr  bool
+	}{
+		{"1048576", 1048576, false},
+		{"512Mi", 512 << 20, false},
+		{"2Gi", 2 << 30, false},
+		{"1G", 1000 * 1000 * 1000, false},
+		{"64Ki", 64 << 10, false},
+		{"5x", 0, true},
+		{"-1Mi", 0, true},
+		{"", 0, true},
+		{"8388607Ti", 8388607 << 40, false},
+		{"9999999Ti", 0, true},
+		{"9223372036854775807K", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseByteSize(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("expected error for %q, got %d", tt.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", tt.input, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("expected %d for %q, got %d", tt.expected, tt.input, got)
+		}
+	}
+}
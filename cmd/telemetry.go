@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/messages"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/telemetry"
+)
+
+const (
+	telemetryEnv         = "K8S_CONTROLLER_TELEMETRY"
+	telemetryEndpointEnv = "K8S_CONTROLLER_TELEMETRY_ENDPOINT"
+)
+
+var (
+	telemetryEnabled  bool
+	telemetryEndpoint string
+)
+
+var telemetryCmd = &cobra.Command{
+	Use:     "telemetry",
+	GroupID: groupController,
+	Short:   "Inspect and flush opt-in usage telemetry",
+	Long: `Inspect and flush opt-in usage telemetry.
+
+Telemetry is disabled unless --telemetry is passed or ` + telemetryEnv + `=true is set.
+When enabled, the command name, the names of the flags that were set (never their
+values), the error category and the duration of each invocation are buffered in
+a local file and sent in batches to --telemetry-endpoint.`,
+}
+
+var telemetryStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether telemetry is enabled and how many events are buffered",
+	Args:  strictArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := newTelemetryClient()
+		pending, err := client.Pending()
+		if err != nil {
+			return errs.Wrap(errs.CodeUnknown, err, "reading telemetry buffer")
+		}
+
+		printResult(messages.TelemetryEnabled, telemetryOptedIn())
+		printResult(messages.TelemetryEndpoint, client.Endpoint)
+		printResult(messages.TelemetryBuffer, client.BufferPath)
+		printResult(messages.TelemetryPending, pending)
+		return nil
+	},
+}
+
+var telemetryFlushCmd = &cobra.Command{
+	Use:   "flush",
+	Short: "Send buffered telemetry events to the configured endpoint",
+	Args:  strictArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := newTelemetryClient()
+		if client.Endpoint == "" {
+			return errs.ValidationFailed("no telemetry endpoint configured, set --telemetry-endpoint or %s", telemetryEndpointEnv)
+		}
+		if err := client.Flush(cmd.Context()); err != nil {
+			return errs.Wrap(errs.CodeUnknown, err, "flushing telemetry")
+		}
+		log.Info().Str("endpoint", client.Endpoint).Msg("Telemetry flushed")
+		return nil
+	},
+}
+
+// telemetryOptedIn reports whether the user enabled telemetry by flag or environment.
+func telemetryOptedIn() bool {
+	if telemetryEnabled {
+		return true
+	}
+	enabled, _ := strconv.ParseBool(os.Getenv(telemetryEnv))
+	return enabled
+}
+
+func newTelemetryClient() *telemetry.Client {
+	endpoint := telemetryEndpoint
+	if endpoint == "" {
+		endpoint = os.Getenv(telemetryEndpointEnv)
+	}
+	return telemetry.NewClient(filepath.Join(stateDir(), "telemetry.ndjson"), endpoint)
+}
+
+// recordTelemetry buffers a usage event for cmd when telemetry is enabled. Failures are
+// only logged at debug level so telemetry can never change the outcome of a command.
+func recordTelemetry(cmd *cobra.Command, err error, duration time.Duration) {
+	if cmd == nil || !telemetryOptedIn() {
+		return
+	}
+
+	var flags []string
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		flags = append(flags, f.Name)
+	})
+
+	event := telemetry.Event{
+		Time:       time.Now().UTC(),
+		Command:    cmd.CommandPath(),
+		Flags:      flags,
+		DurationMs: duration.Milliseconds(),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+	}
+	event.Version, _, _ = buildInfo()
+	if err != nil {
+		event.ErrorCode = string(errs.CodeOf(err))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := newTelemetryClient().Record(ctx, event); err != nil {
+		log.Debug().Err(err).Msg("Failed to record telemetry")
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(telemetryCmd)
+	telemetryCmd.AddCommand(telemetryStatusCmd)
+	telemetryCmd.AddCommand(telemetryFlushCmd)
+
+	rootCmd.PersistentFlags().BoolVar(&telemetryEnabled, "telemetry", false, "Opt in to anonymous usage telemetry (or set "+telemetryEnv+"=true)")
+	rootCmd.PersistentFlags().StringVar(&telemetryEndpoint, "telemetry-endpoint", "", "Endpoint that receives telemetry batches (or set "+telemetryEndpointEnv+")")
+}
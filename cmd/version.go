@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/featuregate"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/messages"
+)
+
+// Build information, set at build time with
+// -ldflags "-X github.com/yourusername/k8s-controller-tutorial/cmd.version=v0.1.0 ..."
+var (
+	version   = "dev"
+	commit    = ""
+	buildDate = ""
+)
+
+// Feature gates known to this binary.
+const (
+	// featureDemoStatePersistence stores the demo cluster in a state file between runs.
+	featureDemoStatePersistence = "DemoStatePersistence"
+)
+
+var featureGates = featuregate.New()
+
+var versionCmd = &cobra.Command{
+	Use:     "version",
+	GroupID: groupController,
+	Short:   "Print build information and feature gates",
+	Args:    strictArgs(cobra.NoArgs),
+	Run: func(cmd *cobra.Command, args []string) {
+		v, c, d := buildInfo()
+		printResult(messages.VersionNumber, v)
+		printResult(messages.VersionCommit, c)
+		printResult(messages.VersionBuildDate, d)
+		printResult(messages.VersionGo, runtime.Version())
+		printResult(messages.VersionFeatureGates, formatFeatureGates())
+	},
+}
+
+// buildInfo returns the version, commit and build date, falling back to the
+// module and VCS information embedded by the Go toolchain when ldflags weren't set.
+func buildInfo() (string, string, string) {
+	v, c, d := version, commit, buildDate
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return v, c, d
+	}
+	if v == "dev" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		v = info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		switch {
+		case setting.Key == "vcs.revision" && c == "":
+			c = setting.Value
+		case setting.Key == "vcs.time" && d == "":
+			d = setting.Value
+		}
+	}
+	return v, c, d
+}
+
+// formatFeatureGates renders the effective state of every feature gate as Name=bool pairs.
+func formatFeatureGates() string {
+	states := featureGates.States()
+	pairs := make([]string, 0, len(states))
+	for _, name := range featureGates.Known() {
+		pairs = append(pairs, fmt.Sprintf("%s=%t", name, states[name]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// logBuildInfo logs the build information and effective feature gates at startup.
+func logBuildInfo() {
+	v, c, d := buildInfo()
+	log.Debug().
+		Str("version", v).
+		Str("commit", c).
+		Str("build_date", d).
+		Str("feature_gates", formatFeatureGates()).
+		Msg("Starting k8s-controller-cli")
+}
+
+func init() {
+	if err := featureGates.Add(featureDemoStatePersistence, featuregate.Spec{Default: true, Stage: featuregate.Beta}); err != nil {
+		panic(err)
+	}
+
+	rootCmd.AddCommand(versionCmd)
+	rootCmd.PersistentFlags().Var(featureGates, "feature-gates",
+		"Comma-separated list of Name=true|false pairs. Known gates:\n"+strings.Join(featureGates.Describe(), "\n"))
+}
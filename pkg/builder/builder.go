@@ -0,0 +1,296 @@
+// Package builder constructs Pods and Deployments with a fluent API:
+//
+//	deployment, err := builder.NewDeployment("web").
+//		Image("nginx:1.27").
+//		Port(80).
+//		ConfigMapVolume("web-config").
+//		Build()
+//
+// Setters never fail; every option is validated when Build is called and all
+// problems are reported together.
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// AppLabel is set to the object name on every built object and selects the pods of a Deployment.
+const AppLabel = "app"
+
+// podTemplate holds the options shared by the Pod and Deployment builders.
+// The workload has a single container named after the object unless renamed.
+type podTemplate struct {
+	name        string
+	namespace   string
+	labels      map[string]string
+	annotations map[string]string
+	container   corev1.Container
+	volumes     []corev1.Volume
+
+	nodeSelector map[string]string
+	tolerations  []corev1.Toleration
+	affinity     *corev1.Affinity
+	security     *corev1.PodSecurityContext
+}
+
+func newPodTemplate(name string) podTemplate {
+	return podTemplate{
+		name:      name,
+		labels:    map[string]string{AppLabel: name},
+		container: corev1.Container{Name: containerName(name)},
+	}
+}
+
+// containerName derives a DNS label from an object name, which is a subdomain:
+// dots become dashes and the result is cut to the label length.
+func containerName(name string) string {
+	label := strings.ReplaceAll(name, ".", "-")
+	if len(label) > validation.DNS1123LabelMaxLength {
+		label = label[:validation.DNS1123LabelMaxLength]
+	}
+	return strings.TrimRight(label, "-")
+}
+
+func (t *podTemplate) setLabel(key, value string) {
+	t.labels[key] = value
+}
+
+func (t *podTemplate) setAnnotation(key, value string) {
+	if t.annotations == nil {
+		t.annotations = map[string]string{}
+	}
+	t.annotations[key] = value
+}
+
+func (t *podTemplate) addPort(port int32) {
+	t.container.Ports = append(t.container.Ports, corev1.ContainerPort{ContainerPort: port, Protocol: corev1.ProtocolTCP})
+}
+
+func (t *podTemplate) addEnv(name, value string) {
+	t.container.Env = append(t.container.Env, corev1.EnvVar{Name: name, Value: value})
+}
+
+func (t *podTemplate) addConfigMapVolume(configMap, mountPath string) {
+	t.volumes = append(t.volumes, corev1.Volume{
+		Name: configMap,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: configMap}},
+		},
+	})
+	t.container.VolumeMounts = append(t.container.VolumeMounts, corev1.VolumeMount{Name: configMap, MountPath: mountPath, ReadOnly: true})
+}
+
+func (t *podTemplate) addSecretVolume(secret, mountPath string) {
+	t.volumes = append(t.volumes, corev1.Volume{
+		Name:         secret,
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: secret}},
+	})
+	t.container.VolumeMounts = append(t.container.VolumeMounts, corev1.VolumeMount{Name: secret, MountPath: mountPath, ReadOnly: true})
+}
+
+func (t *podTemplate) addSecretEnv(name, secret, key string) {
+	t.container.Env = append(t.container.Env, corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: secret},
+			Key:                  key,
+		}},
+	})
+}
+
+func (t *podTemplate) setNodeSelector(key, value string) {
+	if t.nodeSelector == nil {
+		t.nodeSelector = map[string]string{}
+	}
+	t.nodeSelector[key] = value
+}
+
+// podSecurityContext returns the pod-level security context, creating it as needed.
+func (t *podTemplate) podSecurityContext() *corev1.PodSecurityContext {
+	if t.security == nil {
+		t.security = &corev1.PodSecurityContext{}
+	}
+	return t.security
+}
+
+// containerSecurityContext returns the container's security context, creating it as needed.
+func (t *podTemplate) containerSecurityContext() *corev1.SecurityContext {
+	if t.container.SecurityContext == nil {
+		t.container.SecurityContext = &corev1.SecurityContext{}
+	}
+	return t.container.SecurityContext
+}
+
+// validate returns every problem with the options, nil when they are valid.
+func (t *podTemplate) validate() []string {
+	var problems []string
+	for _, msg := range validation.IsDNS1123Subdomain(t.name) {
+		problems = append(problems, fmt.Sprintf("name %q: %s", t.name, msg))
+	}
+	if t.namespace != "" {
+		for _, msg := range validation.IsDNS1123Label(t.namespace) {
+			problems = append(problems, fmt.Sprintf("namespace %q: %s", t.namespace, msg))
+		}
+	}
+	for _, msg := range validation.IsDNS1123Label(t.container.Name) {
+		problems = append(problems, fmt.Sprintf("container name %q: %s", t.container.Name, msg))
+	}
+	for key, value := range t.labels {
+		for _, msg := range validation.IsQualifiedName(key) {
+			problems = append(problems, fmt.Sprintf("label key %q: %s", key, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(value) {
+			problems = append(problems, fmt.Sprintf("label %s value %q: %s", key, value, msg))
+		}
+	}
+	for key := range t.annotations {
+		for _, msg := range validation.IsQualifiedName(key) {
+			problems = append(problems, fmt.Sprintf("annotation key %q: %s", key, msg))
+		}
+	}
+
+	if t.container.Image == "" {
+		problems = append(problems, "image is required")
+	}
+	seenPorts := map[int32]bool{}
+	for _, port := range t.container.Ports {
+		for _, msg := range validation.IsValidPortNum(int(port.ContainerPort)) {
+			problems = append(problems, fmt.Sprintf("port %d: %s", port.ContainerPort, msg))
+		}
+		if seenPorts[port.ContainerPort] {
+			problems = append(problems, fmt.Sprintf("port %d is declared twice", port.ContainerPort))
+		}
+		seenPorts[port.ContainerPort] = true
+	}
+	for _, env := range t.container.Env {
+		for _, msg := range validation.IsEnvVarName(env.Name) {
+			problems = append(problems, fmt.Sprintf("env %q: %s", env.Name, msg))
+		}
+		if env.ValueFrom == nil || env.ValueFrom.SecretKeyRef == nil {
+			continue
+		}
+		ref := env.ValueFrom.SecretKeyRef
+		for _, msg := range validation.IsDNS1123Subdomain(ref.Name) {
+			problems = append(problems, fmt.Sprintf("env %q secret %q: %s", env.Name, ref.Name, msg))
+		}
+		for _, msg := range validation.IsConfigMapKey(ref.Key) {
+			problems = append(problems, fmt.Sprintf("env %q secret key %q: %s", env.Name, ref.Key, msg))
+		}
+	}
+	seenVolumes := map[string]bool{}
+	for _, volume := range t.volumes {
+		source := "config map"
+		if volume.Secret != nil {
+			source = "secret"
+		}
+		// the volume is named after its config map or secret and volume names must be labels
+		for _, msg := range validation.IsDNS1123Label(volume.Name) {
+			problems = append(problems, fmt.Sprintf("%s %q: %s", source, volume.Name, msg))
+		}
+		if seenVolumes[volume.Name] {
+			problems = append(problems, fmt.Sprintf("%s %q is mounted twice", source, volume.Name))
+		}
+		seenVolumes[volume.Name] = true
+	}
+	for key, value := range t.nodeSelector {
+		for _, msg := range validation.IsQualifiedName(key) {
+			problems = append(problems, fmt.Sprintf("node selector key %q: %s", key, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(value) {
+			problems = append(problems, fmt.Sprintf("node selector %s value %q: %s", key, value, msg))
+		}
+	}
+	for _, toleration := range t.tolerations {
+		problems = append(problems, tolerationProblems(toleration)...)
+	}
+	if t.security != nil && t.security.RunAsUser != nil {
+		if uid := *t.security.RunAsUser; uid < 0 || uid > math.MaxInt32 {
+			problems = append(problems, fmt.Sprintf("run as user %d is out of range [0, %d]", uid, math.MaxInt32))
+		}
+		if *t.security.RunAsUser == 0 && t.security.RunAsNonRoot != nil && *t.security.RunAsNonRoot {
+			problems = append(problems, "run as user 0 contradicts run as non-root")
+		}
+	}
+	if security := t.container.SecurityContext; security != nil && security.Capabilities != nil {
+		for _, capability := range security.Capabilities.Drop {
+			if capability == "" {
+				problems = append(problems, "capabilities to drop must not be empty")
+			}
+		}
+	}
+	return problems
+}
+
+// tolerationProblems returns what the API server would reject in a toleration.
+func tolerationProblems(toleration corev1.Toleration) []string {
+	var problems []string
+	if toleration.Key != "" {
+		for _, msg := range validation.IsQualifiedName(toleration.Key) {
+			problems = append(problems, fmt.Sprintf("toleration key %q: %s", toleration.Key, msg))
+		}
+	}
+	switch toleration.Operator {
+	case "", corev1.TolerationOpEqual:
+		if toleration.Key == "" {
+			problems = append(problems, "toleration without a key must use operator Exists")
+		}
+		for _, msg := range validation.IsValidLabelValue(toleration.Value) {
+			problems = append(problems, fmt.Sprintf("toleration %s value %q: %s", toleration.Key, toleration.Value, msg))
+		}
+	case corev1.TolerationOpExists:
+		if toleration.Value != "" {
+			problems = append(problems, fmt.Sprintf("toleration %s with operator Exists must not have a value", toleration.Key))
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("toleration %s has unsupported operator %q", toleration.Key, toleration.Operator))
+	}
+	switch toleration.Effect {
+	case "", corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+	default:
+		problems = append(problems, fmt.Sprintf("toleration %s has unsupported effect %q", toleration.Key, toleration.Effect))
+	}
+	return problems
+}
+
+// podSpec returns a copy of the spec so later builder calls don't affect built objects.
+func (t *podTemplate) podSpec() corev1.PodSpec {
+	spec := corev1.PodSpec{Containers: []corev1.Container{*t.container.DeepCopy()}}
+	for _, volume := range t.volumes {
+		spec.Volumes = append(spec.Volumes, *volume.DeepCopy())
+	}
+	spec.NodeSelector = copyMap(t.nodeSelector)
+	for _, toleration := range t.tolerations {
+		spec.Tolerations = append(spec.Tolerations, *toleration.DeepCopy())
+	}
+	spec.Affinity = t.affinity.DeepCopy()
+	spec.SecurityContext = t.security.DeepCopy()
+	return spec
+}
+
+func copyMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// validationError combines problems into a single ValidationFailed error.
+func validationError(kind, name string, problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return errs.Wrap(errs.CodeValidationFailed, errors.New(strings.Join(problems, "; ")), "invalid %s %q", kind, name)
+}
@@ -0,0 +1,207 @@
+package builder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestBuildDeployment(t *testing.T) {
+	deployment, err := NewDeployment("web").
+		Namespace("payments").
+		Replicas(3).
+		Label("tier", "frontend").
+		Image("nginx:1.27").
+		Port(80).
+		Env("LOG_LEVEL", "debug").
+		ConfigMapVolume("web-config").
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if deployment.Namespace != "payments" || *deployment.Spec.Replicas != 3 {
+		t.Errorf("unexpected metadata %s/%s replicas %d", deployment.Namespace, deployment.Name, *deployment.Spec.Replicas)
+	}
+	if deployment.Spec.Selector.MatchLabels[AppLabel] != "web" || deployment.Spec.Template.Labels["tier"] != "frontend" {
+		t.Errorf("unexpected labels %v, selector %v", deployment.Spec.Template.Labels, deployment.Spec.Selector)
+	}
+	container := deployment.Spec.Template.Spec.Containers[0]
+	if container.Name != "web" || container.Image != "nginx:1.27" || container.Ports[0].ContainerPort != 80 || container.Env[0].Value != "debug" {
+		t.Errorf("unexpected container %+v", container)
+	}
+	volume := deployment.Spec.Template.Spec.Volumes[0]
+	if volume.ConfigMap == nil || volume.ConfigMap.Name != "web-config" || container.VolumeMounts[0].MountPath != "/etc/web-config" {
+		t.Errorf("unexpected config map wiring %+v %+v", volume, container.VolumeMounts)
+	}
+}
+
+func TestBuildPod(t *testing.T) {
+	pod, err := NewPod("job").
+		ContainerName("worker").
+		Image("busybox:1.36").
+		RestartPolicy(corev1.RestartPolicyNever).
+		ConfigMapVolumeAt("settings", "/config").
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pod.Kind != "Pod" || pod.Labels[AppLabel] != "job" || pod.Spec.RestartPolicy != corev1.RestartPolicyNever {
+		t.Errorf("unexpected pod %+v", pod)
+	}
+	if pod.Spec.Containers[0].Name != "worker" || pod.Spec.Containers[0].VolumeMounts[0].MountPath != "/config" {
+		t.Errorf("unexpected container %+v", pod.Spec.Containers[0])
+	}
+}
+
+func TestBuildReportsAllProblems(t *testing.T) {
+	_, err := NewDeployment("Web").
+		Replicas(-1).
+		Port(0).
+		Port(80).
+		Port(80).
+		Env("1BAD", "x").
+		ConfigMapVolume("web.config").
+		Build()
+	if !errs.IsValidationFailed(err) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	for _, want := range []string{`name "Web"`, "image is required", "port 0", "port 80 is declared twice", `env "1BAD"`, `config map "web.config"`, "replicas -1"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+
+	if _, err := NewDeployment("web").Image("nginx").Label(AppLabel, "other").Build(); !errs.IsValidationFailed(err) {
+		t.Errorf("expected overriding the selector label to fail, got %v", err)
+	}
+}
+
+func TestBuildDoesNotShareState(t *testing.T) {
+	b := NewPod("web").Image("nginx").Label("version", "1")
+	first, err := b.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b.Label("version", "2")
+	if first.Labels["version"] != "1" {
+		t.Errorf("expected built pod to be unaffected by later calls, got %v", first.Labels)
+	}
+}
+
+func TestDefaultContainerNameIsALabel(t *testing.T) {
+	pod, err := NewPod("web.v2").Image("nginx").Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pod.Name != "web.v2" || pod.Spec.Containers[0].Name != "web-v2" {
+		t.Errorf("expected pod web.v2 with container web-v2, got %s with %s", pod.Name, pod.Spec.Containers[0].Name)
+	}
+
+	long := strings.Repeat("a", 62) + ".b"
+	if name := containerName(long); name != strings.Repeat("a", 62) {
+		t.Errorf("expected the name cut to 63 characters without a trailing dash, got %q", name)
+	}
+}
+
+func TestBuildSecrets(t *testing.T) {
+	deployment, err := NewDeployment("web").
+		Image("nginx:1.27").
+		SecretVolumeAt("web-tls", "/tls").
+		SecretEnv("DB_PASSWORD", "db", "password").
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	spec := deployment.Spec.Template.Spec
+	if volume := spec.Volumes[0]; volume.Secret == nil || volume.Secret.SecretName != "web-tls" || spec.Containers[0].VolumeMounts[0].MountPath != "/tls" {
+		t.Errorf("unexpected secret wiring %+v %+v", volume, spec.Containers[0].VolumeMounts)
+	}
+	env := spec.Containers[0].Env[0]
+	if env.ValueFrom == nil || env.ValueFrom.SecretKeyRef == nil || env.ValueFrom.SecretKeyRef.Name != "db" || env.ValueFrom.SecretKeyRef.Key != "password" {
+		t.Errorf("unexpected secret env %+v", env)
+	}
+
+	_, err = NewPod("web").
+		Image("nginx").
+		ConfigMapVolume("web").
+		SecretVolume("web").
+		SecretEnv("TOKEN", "api", "bad/key").
+		Build()
+	for _, want := range []string{`secret "web" is mounted twice`, `secret key "bad/key"`} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+}
+
+func TestBuildSchedulingConstraints(t *testing.T) {
+	affinity := &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+			Weight:          100,
+			PodAffinityTerm: corev1.PodAffinityTerm{TopologyKey: "kubernetes.io/hostname"},
+		}},
+	}}
+	deployment, err := NewDeployment("web").
+		Image("nginx:1.27").
+		NodeSelector("disktype", "ssd").
+		Toleration(corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "web", Effect: corev1.TaintEffectNoSchedule}).
+		Affinity(affinity).
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	spec := deployment.Spec.Template.Spec
+	if spec.NodeSelector["disktype"] != "ssd" || spec.Tolerations[0].Value != "web" || spec.Affinity.PodAntiAffinity == nil {
+		t.Errorf("unexpected scheduling constraints %+v %+v %+v", spec.NodeSelector, spec.Tolerations, spec.Affinity)
+	}
+	if spec.Affinity == affinity {
+		t.Error("expected the affinity to be copied")
+	}
+
+	_, err = NewPod("web").
+		Image("nginx").
+		NodeSelector("disk type", "ssd").
+		Toleration(corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpExists, Value: "web"}).
+		Toleration(corev1.Toleration{Key: "gpu", Effect: "Sometimes"}).
+		Build()
+	for _, want := range []string{`node selector key "disk type"`, "toleration dedicated with operator Exists", `unsupported effect "Sometimes"`} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+}
+
+func TestBuildSecurityContext(t *testing.T) {
+	b := NewPod("web").
+		Image("nginx:1.27").
+		RunAsUser(1000).
+		RunAsNonRoot().
+		ReadOnlyRootFilesystem().
+		DropCapabilities("ALL")
+	pod, err := b.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	podSecurity := pod.Spec.SecurityContext
+	if podSecurity == nil || *podSecurity.RunAsUser != 1000 || !*podSecurity.RunAsNonRoot {
+		t.Errorf("unexpected pod security context %+v", podSecurity)
+	}
+	security := pod.Spec.Containers[0].SecurityContext
+	if security == nil || !*security.ReadOnlyRootFilesystem || len(security.Capabilities.Drop) != 1 || security.Capabilities.Drop[0] != "ALL" {
+		t.Errorf("unexpected container security context %+v", security)
+	}
+
+	b.RunAsUser(0)
+	if *pod.Spec.SecurityContext.RunAsUser != 1000 {
+		t.Error("expected the built pod to be unaffected by later calls")
+	}
+	_, err = b.DropCapabilities("").Build()
+	for _, want := range []string{"run as user 0 contradicts run as non-root", "capabilities to drop must not be empty"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+}
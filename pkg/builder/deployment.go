@@ -0,0 +1,182 @@
+package builder
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DeploymentBuilder builds a Deployment of single-container pods. Its selector
+// is app=<name>; labels added with Label go on the Deployment and its pods.
+type DeploymentBuilder struct {
+	template podTemplate
+	replicas int32
+}
+
+// NewDeployment starts a Deployment named name with one replica.
+func NewDeployment(name string) *DeploymentBuilder {
+	return &DeploymentBuilder{template: newPodTemplate(name), replicas: 1}
+}
+
+// Namespace sets the namespace, left empty the Deployment is created in the client's namespace.
+func (b *DeploymentBuilder) Namespace(namespace string) *DeploymentBuilder {
+	b.template.namespace = namespace
+	return b
+}
+
+// Replicas sets the desired number of pods.
+func (b *DeploymentBuilder) Replicas(replicas int32) *DeploymentBuilder {
+	b.replicas = replicas
+	return b
+}
+
+// Label adds a label to the Deployment and its pod template.
+func (b *DeploymentBuilder) Label(key, value string) *DeploymentBuilder {
+	b.template.setLabel(key, value)
+	return b
+}
+
+// Annotation adds an annotation to the pod template.
+func (b *DeploymentBuilder) Annotation(key, value string) *DeploymentBuilder {
+	b.template.setAnnotation(key, value)
+	return b
+}
+
+// ContainerName renames the container, which defaults to the Deployment name with dots replaced by dashes.
+func (b *DeploymentBuilder) ContainerName(name string) *DeploymentBuilder {
+	b.template.container.Name = name
+	return b
+}
+
+// Image sets the container image, e.g. nginx:1.27.
+func (b *DeploymentBuilder) Image(image string) *DeploymentBuilder {
+	b.template.container.Image = image
+	return b
+}
+
+// Port exposes a TCP container port. It can be called several times.
+func (b *DeploymentBuilder) Port(port int32) *DeploymentBuilder {
+	b.template.addPort(port)
+	return b
+}
+
+// Env sets an environment variable on the container.
+func (b *DeploymentBuilder) Env(name, value string) *DeploymentBuilder {
+	b.template.addEnv(name, value)
+	return b
+}
+
+// ConfigMapVolume mounts the config map read-only at /etc/<name>.
+func (b *DeploymentBuilder) ConfigMapVolume(name string) *DeploymentBuilder {
+	return b.ConfigMapVolumeAt(name, "/etc/"+name)
+}
+
+// ConfigMapVolumeAt mounts the config map read-only at mountPath.
+func (b *DeploymentBuilder) ConfigMapVolumeAt(name, mountPath string) *DeploymentBuilder {
+	b.template.addConfigMapVolume(name, mountPath)
+	return b
+}
+
+// SecretVolume mounts the secret read-only at /etc/<name>.
+func (b *DeploymentBuilder) SecretVolume(name string) *DeploymentBuilder {
+	return b.SecretVolumeAt(name, "/etc/"+name)
+}
+
+// SecretVolumeAt mounts the secret read-only at mountPath.
+func (b *DeploymentBuilder) SecretVolumeAt(name, mountPath string) *DeploymentBuilder {
+	b.template.addSecretVolume(name, mountPath)
+	return b
+}
+
+// SecretEnv sets an environment variable on the container from a key of a secret.
+func (b *DeploymentBuilder) SecretEnv(name, secret, key string) *DeploymentBuilder {
+	b.template.addSecretEnv(name, secret, key)
+	return b
+}
+
+// NodeSelector restricts scheduling to nodes labeled key=value. It can be called several times.
+func (b *DeploymentBuilder) NodeSelector(key, value string) *DeploymentBuilder {
+	b.template.setNodeSelector(key, value)
+	return b
+}
+
+// Toleration lets the pods schedule onto nodes with a matching taint. It can be called several times.
+func (b *DeploymentBuilder) Toleration(toleration corev1.Toleration) *DeploymentBuilder {
+	b.template.tolerations = append(b.template.tolerations, toleration)
+	return b
+}
+
+// Affinity sets the node and pod affinity rules, for constraints NodeSelector can't express.
+func (b *DeploymentBuilder) Affinity(affinity *corev1.Affinity) *DeploymentBuilder {
+	b.template.affinity = affinity
+	return b
+}
+
+// RunAsUser runs the processes of the pods as uid.
+func (b *DeploymentBuilder) RunAsUser(uid int64) *DeploymentBuilder {
+	b.template.podSecurityContext().RunAsUser = &uid
+	return b
+}
+
+// RunAsNonRoot makes the kubelet refuse to start containers running as root.
+func (b *DeploymentBuilder) RunAsNonRoot() *DeploymentBuilder {
+	nonRoot := true
+	b.template.podSecurityContext().RunAsNonRoot = &nonRoot
+	return b
+}
+
+// ReadOnlyRootFilesystem mounts the container's root filesystem read-only.
+func (b *DeploymentBuilder) ReadOnlyRootFilesystem() *DeploymentBuilder {
+	readOnly := true
+	b.template.containerSecurityContext().ReadOnlyRootFilesystem = &readOnly
+	return b
+}
+
+// DropCapabilities drops Linux capabilities from the container, e.g. ALL.
+func (b *DeploymentBuilder) DropCapabilities(capabilities ...string) *DeploymentBuilder {
+	security := b.template.containerSecurityContext()
+	if security.Capabilities == nil {
+		security.Capabilities = &corev1.Capabilities{}
+	}
+	for _, capability := range capabilities {
+		security.Capabilities.Drop = append(security.Capabilities.Drop, corev1.Capability(capability))
+	}
+	return b
+}
+
+// Build validates the options and returns the Deployment.
+func (b *DeploymentBuilder) Build() (*appsv1.Deployment, error) {
+	problems := b.template.validate()
+	if b.replicas < 0 {
+		problems = append(problems, fmt.Sprintf("replicas %d must not be negative", b.replicas))
+	}
+	if b.template.labels[AppLabel] != b.template.name {
+		problems = append(problems, fmt.Sprintf("label %s must stay %q, the selector depends on it", AppLabel, b.template.name))
+	}
+	if err := validationError("deployment", b.template.name, problems); err != nil {
+		return nil, err
+	}
+
+	replicas := b.replicas
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      b.template.name,
+			Namespace: b.template.namespace,
+			Labels:    copyMap(b.template.labels),
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{AppLabel: b.template.name}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      copyMap(b.template.labels),
+					Annotations: copyMap(b.template.annotations),
+				},
+				Spec: b.template.podSpec(),
+			},
+		},
+	}, nil
+}
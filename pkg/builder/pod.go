@@ -0,0 +1,169 @@
+package builder
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PodBuilder builds a single-container Pod.
+type PodBuilder struct {
+	template      podTemplate
+	restartPolicy corev1.RestartPolicy
+}
+
+// NewPod starts a Pod named name, labeled app=name, whose container is named after it.
+func NewPod(name string) *PodBuilder {
+	return &PodBuilder{template: newPodTemplate(name)}
+}
+
+// Namespace sets the namespace, left empty the Pod is created in the client's namespace.
+func (b *PodBuilder) Namespace(namespace string) *PodBuilder {
+	b.template.namespace = namespace
+	return b
+}
+
+// Label adds a label to the Pod.
+func (b *PodBuilder) Label(key, value string) *PodBuilder {
+	b.template.setLabel(key, value)
+	return b
+}
+
+// Annotation adds an annotation to the Pod.
+func (b *PodBuilder) Annotation(key, value string) *PodBuilder {
+	b.template.setAnnotation(key, value)
+	return b
+}
+
+// ContainerName renames the container, which defaults to the Pod name with dots replaced by dashes.
+func (b *PodBuilder) ContainerName(name string) *PodBuilder {
+	b.template.container.Name = name
+	return b
+}
+
+// Image sets the container image, e.g. nginx:1.27.
+func (b *PodBuilder) Image(image string) *PodBuilder {
+	b.template.container.Image = image
+	return b
+}
+
+// Port exposes a TCP container port. It can be called several times.
+func (b *PodBuilder) Port(port int32) *PodBuilder {
+	b.template.addPort(port)
+	return b
+}
+
+// Env sets an environment variable on the container.
+func (b *PodBuilder) Env(name, value string) *PodBuilder {
+	b.template.addEnv(name, value)
+	return b
+}
+
+// ConfigMapVolume mounts the config map read-only at /etc/<name>.
+func (b *PodBuilder) ConfigMapVolume(name string) *PodBuilder {
+	return b.ConfigMapVolumeAt(name, "/etc/"+name)
+}
+
+// ConfigMapVolumeAt mounts the config map read-only at mountPath.
+func (b *PodBuilder) ConfigMapVolumeAt(name, mountPath string) *PodBuilder {
+	b.template.addConfigMapVolume(name, mountPath)
+	return b
+}
+
+// SecretVolume mounts the secret read-only at /etc/<name>.
+func (b *PodBuilder) SecretVolume(name string) *PodBuilder {
+	return b.SecretVolumeAt(name, "/etc/"+name)
+}
+
+// SecretVolumeAt mounts the secret read-only at mountPath.
+func (b *PodBuilder) SecretVolumeAt(name, mountPath string) *PodBuilder {
+	b.template.addSecretVolume(name, mountPath)
+	return b
+}
+
+// SecretEnv sets an environment variable on the container from a key of a secret.
+func (b *PodBuilder) SecretEnv(name, secret, key string) *PodBuilder {
+	b.template.addSecretEnv(name, secret, key)
+	return b
+}
+
+// NodeSelector restricts scheduling to nodes labeled key=value. It can be called several times.
+func (b *PodBuilder) NodeSelector(key, value string) *PodBuilder {
+	b.template.setNodeSelector(key, value)
+	return b
+}
+
+// Toleration lets the Pod schedule onto nodes with a matching taint. It can be called several times.
+func (b *PodBuilder) Toleration(toleration corev1.Toleration) *PodBuilder {
+	b.template.tolerations = append(b.template.tolerations, toleration)
+	return b
+}
+
+// Affinity sets the node and pod affinity rules, for constraints NodeSelector can't express.
+func (b *PodBuilder) Affinity(affinity *corev1.Affinity) *PodBuilder {
+	b.template.affinity = affinity
+	return b
+}
+
+// RunAsUser runs the processes of the Pod as uid.
+func (b *PodBuilder) RunAsUser(uid int64) *PodBuilder {
+	b.template.podSecurityContext().RunAsUser = &uid
+	return b
+}
+
+// RunAsNonRoot makes the kubelet refuse to start containers running as root.
+func (b *PodBuilder) RunAsNonRoot() *PodBuilder {
+	nonRoot := true
+	b.template.podSecurityContext().RunAsNonRoot = &nonRoot
+	return b
+}
+
+// ReadOnlyRootFilesystem mounts the container's root filesystem read-only.
+func (b *PodBuilder) ReadOnlyRootFilesystem() *PodBuilder {
+	readOnly := true
+	b.template.containerSecurityContext().ReadOnlyRootFilesystem = &readOnly
+	return b
+}
+
+// DropCapabilities drops Linux capabilities from the container, e.g. ALL.
+func (b *PodBuilder) DropCapabilities(capabilities ...string) *PodBuilder {
+	security := b.template.containerSecurityContext()
+	if security.Capabilities == nil {
+		security.Capabilities = &corev1.Capabilities{}
+	}
+	for _, capability := range capabilities {
+		security.Capabilities.Drop = append(security.Capabilities.Drop, corev1.Capability(capability))
+	}
+	return b
+}
+
+// RestartPolicy sets the restart policy, Always when unset.
+func (b *PodBuilder) RestartPolicy(policy corev1.RestartPolicy) *PodBuilder {
+	b.restartPolicy = policy
+	return b
+}
+
+// Build validates the options and returns the Pod.
+func (b *PodBuilder) Build() (*corev1.Pod, error) {
+	problems := b.template.validate()
+	switch b.restartPolicy {
+	case "", corev1.RestartPolicyAlways, corev1.RestartPolicyOnFailure, corev1.RestartPolicyNever:
+	default:
+		problems = append(problems, "unsupported restart policy "+string(b.restartPolicy))
+	}
+	if err := validationError("pod", b.template.name, problems); err != nil {
+		return nil, err
+	}
+
+	spec := b.template.podSpec()
+	spec.RestartPolicy = b.restartPolicy
+	return &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        b.template.name,
+			Namespace:   b.template.namespace,
+			Labels:      copyMap(b.template.labels),
+			Annotations: copyMap(b.template.annotations),
+		},
+		Spec: spec,
+	}, nil
+}
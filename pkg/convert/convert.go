@@ -0,0 +1,308 @@
+// Package convert moves a pod spec between Pods, Deployments and StatefulSets,
+// keeping labels, annotations, volumes, probes and the rest of the spec intact.
+package convert
+
+import (
+	"strings"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/manifest"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// Kinds Convert can read and produce.
+const (
+	KindPod         = "Pod"
+	KindDeployment  = "Deployment"
+	KindStatefulSet = "StatefulSet"
+)
+
+// serviceAccountVolumePrefix names the token volumes the API server injects into
+// pods; they are regenerated for every new pod and must not be copied.
+const serviceAccountVolumePrefix = "kube-api-access-"
+
+var kindAliases = map[string]string{
+	"pod":          KindPod,
+	"pods":         KindPod,
+	"po":           KindPod,
+	"deployment":   KindDeployment,
+	"deployments":  KindDeployment,
+	"deploy":       KindDeployment,
+	"statefulset":  KindStatefulSet,
+	"statefulsets": KindStatefulSet,
+	"sts":          KindStatefulSet,
+}
+
+// serverAnnotations are set by the API server or kubectl and don't carry over to a new object.
+var serverAnnotations = []string{
+	"deployment.kubernetes.io/revision",
+	"kubectl.kubernetes.io/last-applied-configuration",
+}
+
+// controllerLabels are added to pods by their controllers and would tie the new
+// template to the old controller's revision.
+var controllerLabels = []string{
+	"pod-template-hash",
+	"controller-revision-hash",
+	"statefulset.kubernetes.io/pod-name",
+}
+
+// ParseKind accepts kind names as typed by users ("deploy", "sts", "Pod").
+func ParseKind(kind string) (string, error) {
+	if canonical, ok := kindAliases[strings.ToLower(kind)]; ok {
+		return canonical, nil
+	}
+	return "", errs.ValidationFailed("unsupported kind %q, use pod, deployment or statefulset", kind)
+}
+
+// Options tune the converted object.
+type Options struct {
+	// Replicas overrides the replica count, otherwise it is kept or left to the API default.
+	Replicas *int32
+	// ServiceName is the governing service of a StatefulSet, defaulting to the
+	// source StatefulSet's service or the object name.
+	ServiceName string
+}
+
+// workload is the kind-independent form every conversion goes through.
+type workload struct {
+	meta        metav1.ObjectMeta
+	template    corev1.PodTemplateSpec
+	replicas    *int32
+	selector    *metav1.LabelSelector
+	serviceName string
+	claims      []corev1.PersistentVolumeClaim
+}
+
+// Convert returns obj, a Pod, Deployment or StatefulSet, as kind to.
+func Convert(obj runtime.Object, to string, opts Options) (runtime.Object, error) {
+	w, err := extract(obj)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Replicas != nil {
+		if *opts.Replicas < 0 {
+			return nil, errs.ValidationFailed("replicas %d must not be negative", *opts.Replicas)
+		}
+		w.replicas = opts.Replicas
+	}
+	if opts.ServiceName != "" {
+		w.serviceName = opts.ServiceName
+	}
+
+	if to != KindStatefulSet && len(w.claims) > 0 {
+		names := make([]string, 0, len(w.claims))
+		for _, claim := range w.claims {
+			names = append(names, claim.Name)
+		}
+		return nil, errs.ValidationFailed("%s uses volumeClaimTemplates (%s), which a %s can't express", w.meta.Name, strings.Join(names, ", "), to)
+	}
+	if to != KindPod && w.template.Spec.RestartPolicy != "" && w.template.Spec.RestartPolicy != corev1.RestartPolicyAlways {
+		return nil, errs.ValidationFailed("%s has restartPolicy %s, a %s only allows Always", w.meta.Name, w.template.Spec.RestartPolicy, to)
+	}
+
+	switch to {
+	case KindPod:
+		return toPod(w), nil
+	case KindDeployment:
+		return toDeployment(w), nil
+	case KindStatefulSet:
+		return toStatefulSet(w), nil
+	}
+	return nil, errs.ValidationFailed("unsupported target kind %q", to)
+}
+
+// ConvertManifest converts every object of a multi-document YAML manifest to kind to.
+func ConvertManifest(data []byte, to string, opts Options) ([]byte, error) {
+	docs, err := manifest.Documents(data)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([][]byte, 0, len(docs))
+	for i, doc := range docs {
+		obj, err := decode(doc)
+		if err != nil {
+			return nil, errs.Wrap(errs.CodeValidationFailed, err, "document %d", i)
+		}
+		converted, err := Convert(obj, to, opts)
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := manifest.Marshal(converted)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, encoded)
+	}
+	return manifest.Join(out), nil
+}
+
+// decode reads a single Pod, Deployment or StatefulSet document.
+func decode(doc []byte) (runtime.Object, error) {
+	var meta metav1.TypeMeta
+	if err := yaml.Unmarshal(doc, &meta); err != nil {
+		return nil, err
+	}
+
+	var obj runtime.Object
+	switch {
+	case meta.APIVersion == "v1" && meta.Kind == KindPod:
+		obj = &corev1.Pod{}
+	case meta.APIVersion == "apps/v1" && meta.Kind == KindDeployment:
+		obj = &appsv1.Deployment{}
+	case meta.APIVersion == "apps/v1" && meta.Kind == KindStatefulSet:
+		obj = &appsv1.StatefulSet{}
+	default:
+		return nil, errs.ValidationFailed("unsupported object %s %s, expected v1 Pod, apps/v1 Deployment or apps/v1 StatefulSet", meta.APIVersion, meta.Kind)
+	}
+	if err := yaml.UnmarshalStrict(doc, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func extract(obj runtime.Object) (workload, error) {
+	switch o := obj.(type) {
+	case *corev1.Pod:
+		w := workload{meta: cleanMeta(o.ObjectMeta)}
+		for _, key := range controllerLabels {
+			delete(w.meta.Labels, key)
+		}
+		w.template.Labels = copyMap(w.meta.Labels)
+		if len(w.template.Labels) == 0 {
+			w.template.Labels = map[string]string{"app": o.Name}
+			w.meta.Labels = copyMap(w.template.Labels)
+		}
+		w.template.Annotations = w.meta.Annotations
+		w.meta.Annotations = nil
+		w.template.Spec = *o.Spec.DeepCopy()
+		//scheduling decisions belong to the pod instance, not the template
+		w.template.Spec.NodeName = ""
+		//ephemeral containers are added to running pods for debugging, templates reject them
+		w.template.Spec.EphemeralContainers = nil
+		dropServiceAccountVolumes(&w.template.Spec)
+		return w, nil
+	case *appsv1.Deployment:
+		return workload{
+			meta:     cleanMeta(o.ObjectMeta),
+			template: *o.Spec.Template.DeepCopy(),
+			replicas: o.Spec.Replicas,
+			selector: o.Spec.Selector.DeepCopy(),
+		}, nil
+	case *appsv1.StatefulSet:
+		return workload{
+			meta:        cleanMeta(o.ObjectMeta),
+			template:    *o.Spec.Template.DeepCopy(),
+			replicas:    o.Spec.Replicas,
+			selector:    o.Spec.Selector.DeepCopy(),
+			serviceName: o.Spec.ServiceName,
+			claims:      o.Spec.VolumeClaimTemplates,
+		}, nil
+	}
+	return workload{}, errs.ValidationFailed("unsupported object %T", obj)
+}
+
+func toPod(w workload) *corev1.Pod {
+	meta := w.meta
+	meta.Labels = copyMap(w.template.Labels)
+	meta.Annotations = copyMap(w.template.Annotations)
+	return &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: KindPod},
+		ObjectMeta: meta,
+		Spec:       w.template.Spec,
+	}
+}
+
+func toDeployment(w workload) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: KindDeployment},
+		ObjectMeta: w.meta,
+		Spec: appsv1.DeploymentSpec{
+			Replicas: w.replicas,
+			Selector: selectorFor(w),
+			Template: w.template,
+		},
+	}
+}
+
+func toStatefulSet(w workload) *appsv1.StatefulSet {
+	serviceName := w.serviceName
+	if serviceName == "" {
+		serviceName = w.meta.Name
+	}
+	return &appsv1.StatefulSet{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: KindStatefulSet},
+		ObjectMeta: w.meta,
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:             w.replicas,
+			Selector:             selectorFor(w),
+			ServiceName:          serviceName,
+			Template:             w.template,
+			VolumeClaimTemplates: w.claims,
+		},
+	}
+}
+
+// selectorFor keeps the source selector, or selects on every template label when converting a Pod.
+func selectorFor(w workload) *metav1.LabelSelector {
+	if w.selector != nil {
+		return w.selector
+	}
+	return &metav1.LabelSelector{MatchLabels: copyMap(w.template.Labels)}
+}
+
+// cleanMeta keeps the user-authored metadata and drops what the API server sets.
+func cleanMeta(meta metav1.ObjectMeta) metav1.ObjectMeta {
+	clean := metav1.ObjectMeta{
+		Name:        meta.Name,
+		Namespace:   meta.Namespace,
+		Labels:      copyMap(meta.Labels),
+		Annotations: copyMap(meta.Annotations),
+	}
+	for _, key := range serverAnnotations {
+		delete(clean.Annotations, key)
+	}
+	if len(clean.Annotations) == 0 {
+		clean.Annotations = nil
+	}
+	return clean
+}
+
+// dropServiceAccountVolumes removes the injected token volumes of a pod read from a cluster.
+func dropServiceAccountVolumes(spec *corev1.PodSpec) {
+	var volumes []corev1.Volume
+	for _, volume := range spec.Volumes {
+		if !strings.HasPrefix(volume.Name, serviceAccountVolumePrefix) {
+			volumes = append(volumes, volume)
+		}
+	}
+	spec.Volumes = volumes
+
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for i := range containers {
+			var mounts []corev1.VolumeMount
+			for _, mount := range containers[i].VolumeMounts {
+				if !strings.HasPrefix(mount.Name, serviceAccountVolumePrefix) {
+					mounts = append(mounts, mount)
+				}
+			}
+			containers[i].VolumeMounts = mounts
+		}
+	}
+}
+
+func copyMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
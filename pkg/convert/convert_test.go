@@ -0,0 +1,166 @@
+package convert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const podManifest = `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+  namespace: payments
+  uid: 0d8f7f55-1c59-4a52-9d5e-1a2b3c4d5e6f
+  labels:
+    app: web
+  annotations:
+    prometheus.io/scrape: "true"
+    kubectl.kubernetes.io/last-applied-configuration: '{"kind":"Pod"}'
+spec:
+  nodeName: node-1
+  ephemeralContainers:
+  - name: debugger
+    image: busybox:1.36
+    targetContainerName: web
+  containers:
+  - name: web
+    image: nginx:1.27
+    readinessProbe:
+      httpGet:
+        path: /healthz
+        port: 80
+    volumeMounts:
+    - name: config
+      mountPath: /etc/web
+    - name: kube-api-access-abcde
+      mountPath: /var/run/secrets/kubernetes.io/serviceaccount
+  volumes:
+  - name: config
+    configMap:
+      name: web-config
+  - name: kube-api-access-abcde
+    projected:
+      sources:
+      - serviceAccountToken:
+          path: token
+status:
+  phase: Running
+`
+
+func TestConvertPodToDeployment(t *testing.T) {
+	replicas := int32(3)
+	out, err := ConvertManifest([]byte(podManifest), KindDeployment, Options{Replicas: &replicas})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var deployment appsv1.Deployment
+	if err := yaml.UnmarshalStrict(out, &deployment); err != nil {
+		t.Fatalf("decoding output: %v\n%s", err, out)
+	}
+	if deployment.Name != "web" || deployment.Namespace != "payments" || deployment.UID != "" || *deployment.Spec.Replicas != 3 {
+		t.Errorf("unexpected metadata %+v", deployment.ObjectMeta)
+	}
+	if deployment.Spec.Selector.MatchLabels["app"] != "web" || deployment.Spec.Template.Labels["app"] != "web" {
+		t.Errorf("unexpected selector %v", deployment.Spec.Selector)
+	}
+	if deployment.Spec.Template.Annotations["prometheus.io/scrape"] != "true" {
+		t.Errorf("expected pod annotations on the template, got %v", deployment.Spec.Template.Annotations)
+	}
+	if _, ok := deployment.Spec.Template.Annotations["kubectl.kubernetes.io/last-applied-configuration"]; ok {
+		t.Errorf("expected server annotations to be dropped, got %v", deployment.Spec.Template.Annotations)
+	}
+	spec := deployment.Spec.Template.Spec
+	if spec.NodeName != "" || len(spec.Volumes) != 1 || len(spec.Containers[0].VolumeMounts) != 1 {
+		t.Errorf("expected node name and token volume to be dropped, got %+v", spec)
+	}
+	if len(spec.EphemeralContainers) != 0 {
+		t.Errorf("expected ephemeral containers to be dropped, got %+v", spec.EphemeralContainers)
+	}
+	if spec.Containers[0].ReadinessProbe == nil || spec.Containers[0].ReadinessProbe.HTTPGet.Path != "/healthz" {
+		t.Errorf("expected probes to be preserved, got %+v", spec.Containers[0])
+	}
+	if strings.Contains(string(out), "status") {
+		t.Errorf("expected status to be dropped:\n%s", out)
+	}
+}
+
+func TestConvertStatefulSetRoundTrip(t *testing.T) {
+	out, err := ConvertManifest([]byte(podManifest), KindStatefulSet, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var sts appsv1.StatefulSet
+	if err := yaml.UnmarshalStrict(out, &sts); err != nil {
+		t.Fatalf("decoding output: %v", err)
+	}
+	if sts.Spec.ServiceName != "web" {
+		t.Errorf("expected service name to default to the object name, got %q", sts.Spec.ServiceName)
+	}
+
+	pod, err := ConvertManifest(out, KindPod, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"kind: Pod", "name: web", "prometheus.io/scrape", "/healthz"} {
+		if !strings.Contains(string(pod), want) {
+			t.Errorf("expected %q in extracted pod:\n%s", want, pod)
+		}
+	}
+}
+
+func TestConvertRejectsUnrepresentableSpecs(t *testing.T) {
+	job := strings.Replace(podManifest, "spec:\n", "spec:\n  restartPolicy: Never\n", 1)
+	if _, err := ConvertManifest([]byte(job), KindDeployment, Options{}); !errs.IsValidationFailed(err) {
+		t.Errorf("expected restartPolicy Never to be rejected, got %v", err)
+	}
+
+	negative := int32(-1)
+	if _, err := ConvertManifest([]byte(podManifest), KindDeployment, Options{Replicas: &negative}); !errs.IsValidationFailed(err) {
+		t.Errorf("expected negative replicas to be rejected, got %v", err)
+	}
+
+	sts := `apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  serviceName: db
+  selector:
+    matchLabels:
+      app: db
+  template:
+    metadata:
+      labels:
+        app: db
+    spec:
+      containers:
+      - name: db
+        image: postgres:16
+  volumeClaimTemplates:
+  - metadata:
+      name: data
+`
+	if _, err := ConvertManifest([]byte(sts), KindDeployment, Options{}); !errs.IsValidationFailed(err) || !strings.Contains(err.Error(), "data") {
+		t.Errorf("expected volumeClaimTemplates to be rejected, got %v", err)
+	}
+
+	if _, err := ConvertManifest([]byte("apiVersion: batch/v1\nkind: Job\nmetadata:\n  name: x\n"), KindPod, Options{}); !errs.IsValidationFailed(err) {
+		t.Errorf("expected unsupported kind to be rejected, got %v", err)
+	}
+}
+
+func TestParseKind(t *testing.T) {
+	for input, want := range map[string]string{"deploy": KindDeployment, "StatefulSet": KindStatefulSet, "po": KindPod} {
+		if got, err := ParseKind(input); err != nil || got != want {
+			t.Errorf("%s: expected %s, got %s (%v)", input, want, got, err)
+		}
+	}
+	if _, err := ParseKind("job"); !errs.IsValidationFailed(err) {
+		t.Errorf("expected validation error, got %v", err)
+	}
+}
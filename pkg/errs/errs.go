@@ -0,0 +1,153 @@
+// Package errs provides typed errors shared across the CLI so callers can
+// branch on an error's kind (for retries, exit codes or HTTP statuses)
+// instead of matching on message strings.
+package errs
+
+import (
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Code identifies the kind of failure carried by an Error.
+type Code string
+
+const (
+	// CodeUnknown is reported for errors that were not created by this package.
+	CodeUnknown Code = "Unknown"
+	// CodeNotFound means the requested object does not exist.
+	CodeNotFound Code = "NotFound"
+	// CodeConflict means the object was changed concurrently or already exists.
+	CodeConflict Code = "Conflict"
+	// CodeValidationFailed means user input was rejected before any action was taken.
+	CodeValidationFailed Code = "ValidationFailed"
+	// CodeTimeout means an operation did not finish in the allotted time.
+	CodeTimeout Code = "Timeout"
+)
+
+// exitCodes maps error codes to process exit codes used by the CLI.
+var exitCodes = map[Code]int{
+	CodeUnknown:          1,
+	CodeValidationFailed: 2,
+	CodeNotFound:         3,
+	CodeConflict:         4,
+	CodeTimeout:          5,
+}
+
+// Error is a typed error with an optional wrapped cause.
+type Error struct {
+	Code    Code
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	if e.Message == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", e.Message, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New returns an Error of the given code with a formatted message.
+func New(code Code, format string, args ...interface{}) error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Wrap returns an Error of the given code that wraps err, or nil if err is nil.
+func Wrap(code Code, err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+// FromAPI wraps an error returned by the Kubernetes API server with the code
+// closest to its status reason, or returns nil if err is nil.
+func FromAPI(err error, format string, args ...interface{}) error {
+	return Wrap(apiCode(err), err, format, args...)
+}
+
+// apiCode maps an API server error to the closest error code.
+func apiCode(err error) Code {
+	switch {
+	case apierrors.IsNotFound(err):
+		return CodeNotFound
+	case apierrors.IsConflict(err), apierrors.IsAlreadyExists(err):
+		return CodeConflict
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return CodeValidationFailed
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err):
+		return CodeTimeout
+	}
+	return CodeUnknown
+}
+
+// NotFound returns a CodeNotFound error.
+func NotFound(format string, args ...interface{}) error {
+	return New(CodeNotFound, format, args...)
+}
+
+// Conflict returns a CodeConflict error.
+func Conflict(format string, args ...interface{}) error {
+	return New(CodeConflict, format, args...)
+}
+
+// ValidationFailed returns a CodeValidationFailed error.
+func ValidationFailed(format string, args ...interface{}) error {
+	return New(CodeValidationFailed, format, args...)
+}
+
+// Timeout returns a CodeTimeout error.
+func Timeout(format string, args ...interface{}) error {
+	return New(CodeTimeout, format, args...)
+}
+
+// CodeOf returns the code of the outermost Error in err's chain, or
+// CodeUnknown if there is none.
+func CodeOf(err error) Code {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return CodeUnknown
+}
+
+// IsNotFound reports whether err is a CodeNotFound error.
+func IsNotFound(err error) bool {
+	return CodeOf(err) == CodeNotFound
+}
+
+// IsConflict reports whether err is a CodeConflict error.
+func IsConflict(err error) bool {
+	return CodeOf(err) == CodeConflict
+}
+
+// IsValidationFailed reports whether err is a CodeValidationFailed error.
+func IsValidationFailed(err error) bool {
+	return CodeOf(err) == CodeValidationFailed
+}
+
+// IsTimeout reports whether err is a CodeTimeout error.
+func IsTimeout(err error) bool {
+	return CodeOf(err) == CodeTimeout
+}
+
+// ExitCode returns the process exit code for err, 0 when err is nil and 1 when
+// its code has no dedicated exit code.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if code, ok := exitCodes[CodeOf(err)]; ok {
+		return code
+	}
+	return 1
+}
@@ -0,0 +1,95 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestCodeOf(t *testing.T) {
+	cause := errors.New("connection reset")
+	tests := []struct {
+		name     string
+		err      error
+		expected Code
+	}{
+		{"not found", NotFound("pod %q not found", "web"), CodeNotFound},
+		{"wrapped timeout", Wrap(CodeTimeout, cause, "waiting for rollout"), CodeTimeout},
+		{"fmt wrapped", fmt.Errorf("add-node: %w", ValidationFailed("bad count")), CodeValidationFailed},
+		{"plain error", cause, CodeUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := CodeOf(tt.err); got != tt.expected {
+			t.Errorf("%s: expected code %s, got %s", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestWrapKeepsCause(t *testing.T) {
+	cause := errors.New("connection reset")
+	err := Wrap(CodeConflict, cause, "updating deployment")
+
+	if !errors.Is(err, cause) {
+		t.Errorf("expected wrapped error to match its cause")
+	}
+	if !IsConflict(err) {
+		t.Errorf("expected conflict error, got %s", CodeOf(err))
+	}
+	if err.Error() != "updating deployment: connection reset" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+	if Wrap(CodeConflict, nil, "noop") != nil {
+		t.Errorf("expected nil when wrapping a nil error")
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	if code := ExitCode(nil); code != 0 {
+		t.Errorf("expected 0 for nil error, got %d", code)
+	}
+	if code := ExitCode(errors.New("boom")); code != 1 {
+		t.Errorf("expected 1 for unknown error, got %d", code)
+	}
+	if code := ExitCode(ValidationFailed("bad input")); code != 2 {
+		t.Errorf("expected 2 for validation error, got %d", code)
+	}
+	if code := ExitCode(New(Code("Unmapped"), "boom")); code != 1 {
+		t.Errorf("expected 1 for a code without exit code, got %d", code)
+	}
+}
+
+func TestFromAPI(t *testing.T) {
+	pods := schema.GroupResource{Resource: "pods"}
+	tests := []struct {
+		name     string
+		err      error
+		expected Code
+	}{
+		{"not found", apierrors.NewNotFound(pods, "web"), CodeNotFound},
+		{"conflict", apierrors.NewConflict(pods, "web", errors.New("modified")), CodeConflict},
+		{"already exists", apierrors.NewAlreadyExists(pods, "web"), CodeConflict},
+		{"invalid", apierrors.NewInvalid(schema.GroupKind{Kind: "Pod"}, "web", nil), CodeValidationFailed},
+		{"bad request", apierrors.NewBadRequest("bad"), CodeValidationFailed},
+		{"timeout", apierrors.NewTimeoutError("slow", 1), CodeTimeout},
+		{"server timeout", apierrors.NewServerTimeout(pods, "get", 1), CodeTimeout},
+		{"forbidden", apierrors.NewForbidden(pods, "web", errors.New("denied")), CodeUnknown},
+		{"plain error", errors.New("connection reset"), CodeUnknown},
+	}
+
+	for _, tt := range tests {
+		err := FromAPI(tt.err, "getting pod %s", "web")
+		if got := CodeOf(err); got != tt.expected {
+			t.Errorf("%s: expected code %s, got %s", tt.name, tt.expected, got)
+		}
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: expected the API error to be wrapped", tt.name)
+		}
+	}
+	if FromAPI(nil, "noop") != nil {
+		t.Errorf("expected nil for a nil error")
+	}
+}
@@ -0,0 +1,62 @@
+package featuregate
+
+import (
+	"testing"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+)
+
+func newTestGate(t *testing.T) *Gate {
+	g := New()
+	if err := g.Add("Stable", Spec{Default: true, Stage: Beta}); err != nil {
+		t.Fatalf("failed to add gate: %v", err)
+	}
+	if err := g.Add("Experimental", Spec{Default: false, Stage: Alpha}); err != nil {
+		t.Fatalf("failed to add gate: %v", err)
+	}
+	return g
+}
+
+func TestDefaults(t *testing.T) {
+	g := newTestGate(t)
+
+	if !g.Enabled("Stable") {
+		t.Errorf("expected Stable to be enabled by default")
+	}
+	if g.Enabled("Experimental") {
+		t.Errorf("expected Experimental to be disabled by default")
+	}
+	if g.Enabled("Unregistered") {
+		t.Errorf("expected unregistered gates to be disabled")
+	}
+}
+
+func TestSet(t *testing.T) {
+	g := newTestGate(t)
+
+	if err := g.Set("Stable=false, Experimental=true"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g.Enabled("Stable") || !g.Enabled("Experimental") {
+		t.Errorf("expected overrides to apply, got %v", g.States())
+	}
+	if g.String() != "Experimental=true,Stable=false" {
+		t.Errorf("unexpected string form %q", g.String())
+	}
+}
+
+func TestSetRejectsInvalidInput(t *testing.T) {
+	for _, value := range []string{"Unknown=true", "Stable", "Stable=maybe"} {
+		g := newTestGate(t)
+		if err := g.Set(value); !errs.IsValidationFailed(err) {
+			t.Errorf("expected validation error for %q, got %v", value, err)
+		}
+	}
+}
+
+func TestAddDuplicate(t *testing.T) {
+	g := newTestGate(t)
+	if err := g.Add("Stable", Spec{}); err == nil {
+		t.Errorf("expected error when registering a gate twice")
+	}
+}
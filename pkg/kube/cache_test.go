@@ -0,0 +1,55 @@
+package kube
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func testConfigMap(namespace, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubectl"}})
+	return obj
+}
+
+func TestDumpCache(t *testing.T) {
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{gvr: "ConfigMapList"},
+		testConfigMap("default", "b"),
+		testConfigMap("default", "a"),
+		testConfigMap("kube-system", "c"),
+	)
+
+	objects, err := DumpCache(context.Background(), client, CacheDumpOptions{Resource: gvr})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names []string
+	for _, obj := range objects {
+		names = append(names, obj.GetNamespace()+"/"+obj.GetName())
+		if obj.GetManagedFields() != nil {
+			t.Errorf("%s: expected managedFields to be dropped", obj.GetName())
+		}
+	}
+	if len(names) != 3 || names[0] != "default/a" || names[1] != "default/b" || names[2] != "kube-system/c" {
+		t.Errorf("unexpected objects %v", names)
+	}
+
+	objects, err = DumpCache(context.Background(), client, CacheDumpOptions{Resource: gvr, Namespace: "kube-system", KeepManagedFields: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(objects) != 1 || objects[0].GetManagedFields() == nil {
+		t.Errorf("expected the kube-system object with managedFields, got %v", objects)
+	}
+}
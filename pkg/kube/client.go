@@ -0,0 +1,101 @@
+// Package kube builds Kubernetes clients from kubeconfig files, following the
+// same loading rules as kubectl (--kubeconfig, then KUBECONFIG, then ~/.kube/config).
+package kube
+
+import (
+	"os"
+	"time"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Options select the kubeconfig file and context used to build clients.
+type Options struct {
+	Kubeconfig string
+	Context    string
+	Namespace  string
+	Timeout    time.Duration
+}
+
+// ClientConfig returns the merged client configuration for the options.
+func (o Options) ClientConfig() clientcmd.ClientConfig {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = o.Kubeconfig
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: o.Context}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+}
+
+// NamespaceEnvVars are consulted in order when no namespace is given explicitly.
+// POD_NAMESPACE is what the downward API conventionally sets inside a cluster.
+var NamespaceEnvVars = []string{"POD_NAMESPACE", "NAMESPACE"}
+
+// ResolveNamespace returns the namespace every command operates in: the explicit
+// namespace, then the first of NamespaceEnvVars that is set, then the namespace
+// of the selected context and finally "default".
+func (o Options) ResolveNamespace() string {
+	if o.Namespace != "" {
+		return o.Namespace
+	}
+	for _, name := range NamespaceEnvVars {
+		if namespace := os.Getenv(name); namespace != "" {
+			return namespace
+		}
+	}
+	namespace, _, err := o.ClientConfig().Namespace()
+	if err != nil || namespace == "" {
+		return "default"
+	}
+	return namespace
+}
+
+// RawConfig returns the merged kubeconfig with all contexts.
+func (o Options) RawConfig() (clientcmdapi.Config, error) {
+	config, err := o.ClientConfig().RawConfig()
+	if err != nil {
+		return clientcmdapi.Config{}, errs.Wrap(errs.CodeValidationFailed, err, "loading kubeconfig")
+	}
+	return config, nil
+}
+
+// RESTConfig returns the REST configuration for the selected context.
+func (o Options) RESTConfig() (*rest.Config, error) {
+	config, err := o.ClientConfig().ClientConfig()
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeValidationFailed, err, "loading kubeconfig")
+	}
+	if o.Timeout > 0 {
+		config.Timeout = o.Timeout
+	}
+	return config, nil
+}
+
+// Clientset returns a typed clientset for the selected context.
+func (o Options) Clientset() (*kubernetes.Clientset, error) {
+	config, err := o.RESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeValidationFailed, err, "creating Kubernetes client")
+	}
+	return clientset, nil
+}
+
+// DynamicClient returns a dynamic client for the selected context.
+func (o Options) DynamicClient() (dynamic.Interface, error) {
+	config, err := o.RESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeValidationFailed, err, "creating dynamic Kubernetes client")
+	}
+	return client, nil
+}
@@ -0,0 +1,60 @@
+package kube
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const namespaceKubeconfig = `apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: dev
+  cluster:
+    server: https://127.0.0.1:6443
+users:
+- name: dev
+  user:
+    token: secret
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    user: dev
+    namespace: from-context
+- name: bare
+  context:
+    cluster: dev
+    user: dev
+`
+
+func TestResolveNamespace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(namespaceKubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		options      Options
+		podNamespace string
+		namespace    string
+		want         string
+	}{
+		{"flag wins", Options{Kubeconfig: path, Namespace: "from-flag"}, "from-pod", "from-env", "from-flag"},
+		{"POD_NAMESPACE before NAMESPACE", Options{Kubeconfig: path}, "from-pod", "from-env", "from-pod"},
+		{"NAMESPACE before context", Options{Kubeconfig: path}, "", "from-env", "from-env"},
+		{"context namespace", Options{Kubeconfig: path}, "", "", "from-context"},
+		{"default", Options{Kubeconfig: path, Context: "bare"}, "", "", "default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("POD_NAMESPACE", tt.podNamespace)
+			t.Setenv("NAMESPACE", tt.namespace)
+			if got := tt.options.ResolveNamespace(); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package kube
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Credential types reported by InspectCredentials.
+const (
+	CredentialClientCertificate = "client-certificate"
+	CredentialToken             = "token"
+	CredentialExec              = "exec"
+	CredentialAuthProvider      = "auth-provider"
+	CredentialBasic             = "basic"
+	CredentialNone              = "none"
+)
+
+// CredentialStatus describes the credential used by a kubeconfig context.
+type CredentialStatus struct {
+	Context string `json:"context"`
+	Cluster string `json:"cluster"`
+	User    string `json:"user"`
+	Type    string `json:"type"`
+	// Subject is the certificate subject or token subject, when it can be read locally.
+	Subject string `json:"subject,omitempty"`
+	// Expiry is nil when the credential doesn't expire or its expiry can't be read locally.
+	Expiry *time.Time `json:"expiry,omitempty"`
+	Detail string     `json:"detail,omitempty"`
+}
+
+// InspectCredentials reports the credential of contextName, or of the current context when empty.
+func InspectCredentials(config clientcmdapi.Config, contextName string) (CredentialStatus, error) {
+	if contextName == "" {
+		contextName = config.CurrentContext
+	}
+	kubeContext, ok := config.Contexts[contextName]
+	if !ok {
+		return CredentialStatus{}, errs.NotFound("context %q not found in kubeconfig", contextName)
+	}
+	status := CredentialStatus{
+		Context: contextName,
+		Cluster: kubeContext.Cluster,
+		User:    kubeContext.AuthInfo,
+		Type:    CredentialNone,
+	}
+	auth, ok := config.AuthInfos[kubeContext.AuthInfo]
+	if !ok {
+		return status, nil
+	}
+
+	var err error
+	switch {
+	case len(auth.ClientCertificateData) > 0 || auth.ClientCertificate != "":
+		status.Type = CredentialClientCertificate
+		err = inspectCertificate(auth, &status)
+	case auth.Token != "" || auth.TokenFile != "":
+		status.Type = CredentialToken
+		err = inspectToken(auth, &status)
+	case auth.Exec != nil:
+		status.Type = CredentialExec
+		status.Detail = "expiry is managed by " + auth.Exec.Command
+	case auth.AuthProvider != nil:
+		status.Type = CredentialAuthProvider
+		status.Detail = "expiry is managed by the " + auth.AuthProvider.Name + " auth provider"
+	case auth.Username != "":
+		status.Type = CredentialBasic
+		status.Subject = auth.Username
+	}
+	return status, err
+}
+
+func inspectCertificate(auth *clientcmdapi.AuthInfo, status *CredentialStatus) error {
+	data := auth.ClientCertificateData
+	if len(data) == 0 {
+		var err error
+		data, err = os.ReadFile(auth.ClientCertificate)
+		if err != nil {
+			return errs.Wrap(errs.CodeNotFound, err, "reading client certificate")
+		}
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return errs.ValidationFailed("client certificate is not PEM encoded")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return errs.Wrap(errs.CodeValidationFailed, err, "parsing client certificate")
+	}
+	status.Subject = cert.Subject.String()
+	status.Expiry = &cert.NotAfter
+	return nil
+}
+
+func inspectToken(auth *clientcmdapi.AuthInfo, status *CredentialStatus) error {
+	token := auth.Token
+	if token == "" {
+		data, err := os.ReadFile(auth.TokenFile)
+		if err != nil {
+			return errs.Wrap(errs.CodeNotFound, err, "reading token file")
+		}
+		token = strings.TrimSpace(string(data))
+	}
+
+	//only JWTs carry an expiry that can be read without asking the server
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		status.Detail = "opaque token, expiry unknown"
+		return nil
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		status.Detail = "token payload is not valid base64"
+		return nil
+	}
+	var claims struct {
+		Subject string `json:"sub"`
+		Expiry  int64  `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		status.Detail = "token payload is not valid JSON"
+		return nil
+	}
+
+	status.Subject = claims.Subject
+	if claims.Expiry == 0 {
+		status.Detail = "token does not expire"
+		return nil
+	}
+	expiry := time.Unix(claims.Expiry, 0).UTC()
+	status.Expiry = &expiry
+	return nil
+}
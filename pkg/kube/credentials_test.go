@@ -0,0 +1,80 @@
+package kube
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func testConfig(auth *clientcmdapi.AuthInfo) clientcmdapi.Config {
+	config := clientcmdapi.NewConfig()
+	config.Contexts["dev"] = &clientcmdapi.Context{Cluster: "dev-cluster", AuthInfo: "dev-user"}
+	config.AuthInfos["dev-user"] = auth
+	config.CurrentContext = "dev"
+	return *config
+}
+
+func TestInspectClientCertificate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	notAfter := time.Now().Add(48 * time.Hour).Truncate(time.Second).UTC()
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "alice", Organization: []string{"system:masters"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	status, err := InspectCredentials(testConfig(&clientcmdapi.AuthInfo{ClientCertificateData: certPEM}), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.Type != CredentialClientCertificate {
+		t.Errorf("expected client certificate, got %s", status.Type)
+	}
+	if status.Expiry == nil || !status.Expiry.Equal(notAfter) {
+		t.Errorf("expected expiry %s, got %v", notAfter, status.Expiry)
+	}
+}
+
+func TestInspectJWTToken(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"system:serviceaccount:ci:deployer","exp":1893456000}`))
+	token := "eyJhbGciOiJSUzI1NiJ9." + payload + ".signature"
+
+	status, err := InspectCredentials(testConfig(&clientcmdapi.AuthInfo{Token: token}), "dev")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.Subject != "system:serviceaccount:ci:deployer" {
+		t.Errorf("unexpected subject %q", status.Subject)
+	}
+	if status.Expiry == nil || status.Expiry.Unix() != 1893456000 {
+		t.Errorf("unexpected expiry %v", status.Expiry)
+	}
+}
+
+func TestInspectOpaqueToken(t *testing.T) {
+	status, err := InspectCredentials(testConfig(&clientcmdapi.AuthInfo{Token: "abc"}), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.Type != CredentialToken || status.Expiry != nil {
+		t.Errorf("expected token without expiry, got %+v", status)
+	}
+}
@@ -0,0 +1,100 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Controller installation states reported by ProbeCluster.
+const (
+	ControllerInstalled    = "Installed"
+	ControllerNotInstalled = "NotInstalled"
+	ControllerUnknown      = "Unknown"
+)
+
+// ClusterStatus summarizes the reachability and shape of one cluster.
+type ClusterStatus struct {
+	Context       string `json:"context"`
+	Server        string `json:"server"`
+	Reachable     bool   `json:"reachable"`
+	ServerVersion string `json:"serverVersion,omitempty"`
+	Nodes         int    `json:"nodes"`
+	ReadyNodes    int    `json:"readyNodes"`
+	Controller    string `json:"controller"`
+	// ControllerReady is "ready/desired" replicas of the controller deployment when installed.
+	ControllerReady string `json:"controllerReady,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
+
+// ProbeCluster fills in the status of a single cluster. controllerNamespace and
+// controllerName identify the controller deployment whose installation is checked.
+func ProbeCluster(ctx context.Context, client kubernetes.Interface, status *ClusterStatus, controllerNamespace, controllerName string) {
+	version, err := client.Discovery().ServerVersion()
+	if err != nil {
+		status.Error = err.Error()
+		status.Controller = ControllerUnknown
+		return
+	}
+	status.Reachable = true
+	status.ServerVersion = version.GitVersion
+
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		status.Error = fmt.Sprintf("listing nodes: %v", err)
+	} else {
+		status.Nodes = len(nodes.Items)
+		for _, node := range nodes.Items {
+			for _, condition := range node.Status.Conditions {
+				if condition.Type == "Ready" && condition.Status == "True" {
+					status.ReadyNodes++
+				}
+			}
+		}
+	}
+
+	deployment, err := client.AppsV1().Deployments(controllerNamespace).Get(ctx, controllerName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		status.Controller = ControllerNotInstalled
+	case err != nil:
+		status.Controller = ControllerUnknown
+	default:
+		status.Controller = ControllerInstalled
+		desired := int32(1)
+		if deployment.Spec.Replicas != nil {
+			desired = *deployment.Spec.Replicas
+		}
+		status.ControllerReady = fmt.Sprintf("%d/%d", deployment.Status.ReadyReplicas, desired)
+	}
+}
+
+// ContextOptions returns options targeting each named context of the kubeconfig,
+// or every context when names is empty.
+func (o Options) ContextOptions(names []string) ([]Options, error) {
+	raw, err := o.RawConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(names) == 0 {
+		for name := range raw.Contexts {
+			names = append(names, name)
+		}
+	}
+
+	options := make([]Options, 0, len(names))
+	for _, name := range names {
+		if _, ok := raw.Contexts[name]; !ok {
+			return nil, errs.NotFound("context %q not found in kubeconfig", name)
+		}
+		contextOptions := o
+		contextOptions.Context = name
+		options = append(options, contextOptions)
+	}
+	return options, nil
+}
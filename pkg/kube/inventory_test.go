@@ -0,0 +1,57 @@
+package kube
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func readyNode(name string, ready corev1.ConditionStatus) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+		},
+	}
+}
+
+func TestProbeCluster(t *testing.T) {
+	replicas := int32(2)
+	client := fake.NewSimpleClientset(
+		readyNode("node-a", corev1.ConditionTrue),
+		readyNode("node-b", corev1.ConditionFalse),
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "k8s-controller", Namespace: "k8s-controller-system"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: 1},
+		},
+	)
+
+	status := ClusterStatus{Context: "test"}
+	ProbeCluster(context.Background(), client, &status, "k8s-controller-system", "k8s-controller")
+
+	if !status.Reachable {
+		t.Fatalf("expected cluster to be reachable, got error %q", status.Error)
+	}
+	if status.Nodes != 2 || status.ReadyNodes != 1 {
+		t.Errorf("expected 1/2 ready nodes, got %d/%d", status.ReadyNodes, status.Nodes)
+	}
+	if status.Controller != ControllerInstalled || status.ControllerReady != "1/2" {
+		t.Errorf("expected installed controller with 1/2 replicas, got %s %s", status.Controller, status.ControllerReady)
+	}
+}
+
+func TestProbeClusterWithoutController(t *testing.T) {
+	client := fake.NewSimpleClientset(readyNode("node-a", corev1.ConditionTrue))
+
+	status := ClusterStatus{Context: "test"}
+	ProbeCluster(context.Background(), client, &status, "k8s-controller-system", "k8s-controller")
+
+	if status.Controller != ControllerNotInstalled {
+		t.Errorf("expected controller to be reported as not installed, got %s", status.Controller)
+	}
+}
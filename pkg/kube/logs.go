@@ -0,0 +1,232 @@
+package kube
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// maxLogLineSize bounds a single log line, longer lines end the stream with an error.
+const maxLogLineSize = 1 << 20
+
+// LogOptions selects the pods and containers whose logs are streamed.
+type LogOptions struct {
+	Namespace string
+	Selector  labels.Selector
+	// Container limits streaming to containers with this name, all containers when empty.
+	Container string
+	// Follow keeps streaming and picks up pods and container restarts as they appear.
+	Follow bool
+	// Since and Tail only apply to containers already running when streaming starts.
+	Since time.Duration
+	Tail  int64
+	// Filter drops lines that don't match, nil keeps every line.
+	Filter *regexp.Regexp
+}
+
+// LogLine is a single line of container output. Err is set instead of Text when
+// the stream of that container failed.
+type LogLine struct {
+	Pod       string
+	Container string
+	Text      string
+	Err       error
+}
+
+// StreamLogs streams the logs of every container in the pods matching opts and
+// passes each line to handle. Calls to handle are serialized. Without Follow it
+// returns once all streams are drained; with Follow it runs until ctx is done.
+func StreamLogs(ctx context.Context, client kubernetes.Interface, opts LogOptions, handle func(LogLine)) error {
+	t := &logTailer{
+		client:  client,
+		opts:    opts,
+		handle:  handle,
+		started: map[string]bool{},
+		active:  map[string]context.CancelFunc{},
+	}
+
+	if !opts.Follow {
+		pods, err := client.CoreV1().Pods(opts.Namespace).List(ctx, metav1.ListOptions{LabelSelector: opts.Selector.String()})
+		if err != nil {
+			return errs.FromAPI(err, "listing pods")
+		}
+		if len(pods.Items) == 0 {
+			return errs.NotFound("no pods match %s in namespace %s", opts.Selector, opts.Namespace)
+		}
+		for i := range pods.Items {
+			t.sync(ctx, &pods.Items[i], true)
+		}
+		t.wg.Wait()
+		return nil
+	}
+
+	factory := informers.NewSharedInformerFactoryWithOptions(client, 0,
+		informers.WithNamespace(opts.Namespace),
+		informers.WithTweakListOptions(func(o *metav1.ListOptions) {
+			o.LabelSelector = opts.Selector.String()
+		}))
+	informer := factory.Core().V1().Pods().Informer()
+
+	//pods listed by the initial sync honor Since/Tail, later ones are streamed from the start
+	var synced bool
+	var syncedMu sync.RWMutex
+	initial := func() bool {
+		syncedMu.RLock()
+		defer syncedMu.RUnlock()
+		return !synced
+	}
+	registration, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if pod, ok := obj.(*corev1.Pod); ok {
+				t.sync(ctx, pod, initial())
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if pod, ok := obj.(*corev1.Pod); ok {
+				t.sync(ctx, pod, initial())
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if pod, ok := obj.(*corev1.Pod); ok {
+				t.stop(pod)
+			}
+		},
+	})
+	if err != nil {
+		return errs.FromAPI(err, "watching pods")
+	}
+
+	factory.Start(ctx.Done())
+	//an interrupted sync isn't an error, streaming simply stops
+	if cache.WaitForCacheSync(ctx.Done(), registration.HasSynced) {
+		syncedMu.Lock()
+		synced = true
+		syncedMu.Unlock()
+	}
+
+	<-ctx.Done()
+	factory.Shutdown()
+	t.wg.Wait()
+	return nil
+}
+
+// logTailer tracks one stream per container instance.
+type logTailer struct {
+	client kubernetes.Interface
+	opts   LogOptions
+
+	handleMu sync.Mutex
+	handle   func(LogLine)
+
+	mu sync.Mutex
+	// started holds every container instance ever streamed so a finished stream isn't replayed.
+	started map[string]bool
+	active  map[string]context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// sync starts streams for the containers of pod that have output to read.
+// A restarted container is a new instance and gets a new stream.
+func (t *logTailer) sync(ctx context.Context, pod *corev1.Pod, initial bool) {
+	for _, status := range pod.Status.ContainerStatuses {
+		if t.opts.Container != "" && status.Name != t.opts.Container {
+			continue
+		}
+		if status.State.Running == nil && (t.opts.Follow || status.State.Terminated == nil) {
+			continue
+		}
+
+		key := fmt.Sprintf("%s/%s/%d", pod.UID, status.Name, status.RestartCount)
+		t.mu.Lock()
+		if t.started[key] {
+			t.mu.Unlock()
+			continue
+		}
+		streamCtx, cancel := context.WithCancel(ctx)
+		t.started[key] = true
+		t.active[key] = cancel
+		t.wg.Add(1)
+		t.mu.Unlock()
+
+		go t.stream(streamCtx, key, pod.Name, status.Name, initial)
+	}
+}
+
+// stop cancels the streams of a deleted pod.
+func (t *logTailer) stop(pod *corev1.Pod) {
+	prefix := string(pod.UID) + "/"
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key, cancel := range t.active {
+		if strings.HasPrefix(key, prefix) {
+			cancel()
+		}
+	}
+}
+
+func (t *logTailer) stream(ctx context.Context, key, pod, container string, initial bool) {
+	defer t.wg.Done()
+	defer func() {
+		t.mu.Lock()
+		if cancel, ok := t.active[key]; ok {
+			cancel()
+			delete(t.active, key)
+		}
+		t.mu.Unlock()
+	}()
+
+	podOpts := &corev1.PodLogOptions{Container: container, Follow: t.opts.Follow}
+	if initial {
+		if t.opts.Since > 0 {
+			seconds := int64(t.opts.Since.Seconds())
+			podOpts.SinceSeconds = &seconds
+		}
+		if t.opts.Tail >= 0 {
+			tail := t.opts.Tail
+			podOpts.TailLines = &tail
+		}
+	}
+
+	body, err := t.client.CoreV1().Pods(t.opts.Namespace).GetLogs(pod, podOpts).Stream(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			t.emit(LogLine{Pod: pod, Container: container, Err: err})
+		}
+		return
+	}
+	defer body.Close()
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), maxLogLineSize)
+	for scanner.Scan() {
+		text := scanner.Text()
+		if t.opts.Filter != nil && !t.opts.Filter.MatchString(text) {
+			continue
+		}
+		t.emit(LogLine{Pod: pod, Container: container, Text: text})
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		t.emit(LogLine{Pod: pod, Container: container, Err: err})
+	}
+}
+
+func (t *logTailer) emit(line LogLine) {
+	t.handleMu.Lock()
+	defer t.handleMu.Unlock()
+	t.handle(line)
+}
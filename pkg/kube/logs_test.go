@@ -0,0 +1,115 @@
+package kube
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func runningPod(name string, containers ...string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID("uid-" + name), Labels: map[string]string{"app": "web"}},
+	}
+	for _, c := range containers {
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+			Name:  c,
+			State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		})
+	}
+	return pod
+}
+
+func TestStreamLogs(t *testing.T) {
+	waiting := runningPod("web-3", "app")
+	waiting.Status.ContainerStatuses[0].State = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{}}
+	client := fake.NewSimpleClientset(runningPod("web-1", "app", "proxy"), runningPod("web-2", "app"), waiting)
+
+	opts := LogOptions{
+		Namespace: "default",
+		Selector:  labels.SelectorFromSet(labels.Set{"app": "web"}),
+		Tail:      -1,
+	}
+	var got []string
+	err := StreamLogs(context.Background(), client, opts, func(line LogLine) {
+		if line.Err != nil {
+			t.Errorf("unexpected stream error: %v", line.Err)
+			return
+		}
+		got = append(got, line.Pod+"/"+line.Container+": "+line.Text)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sort.Strings(got)
+	want := []string{"web-1/app: fake logs", "web-1/proxy: fake logs", "web-2/app: fake logs"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("expected %q, got %q", want[i], got[i])
+		}
+	}
+
+	opts.Container = "proxy"
+	opts.Filter = regexp.MustCompile("nothing")
+	got = nil
+	if err := StreamLogs(context.Background(), client, opts, func(line LogLine) { got = append(got, line.Text) }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected filtered lines to be dropped, got %v", got)
+	}
+
+	opts.Selector = labels.SelectorFromSet(labels.Set{"app": "api"})
+	if err := StreamLogs(context.Background(), client, opts, func(LogLine) {}); !errs.IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestStreamLogsFollowPicksUpNewPods(t *testing.T) {
+	client := fake.NewSimpleClientset(runningPod("web-1", "app"))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	lines := make(chan string, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- StreamLogs(ctx, client, LogOptions{
+			Namespace: "default",
+			Selector:  labels.SelectorFromSet(labels.Set{"app": "web"}),
+			Follow:    true,
+			Tail:      -1,
+		}, func(line LogLine) { lines <- line.Pod })
+	}()
+
+	if pod := <-lines; pod != "web-1" {
+		t.Fatalf("expected web-1, got %s", pod)
+	}
+	if _, err := client.CoreV1().Pods("default").Create(ctx, runningPod("web-2", "app"), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("creating pod: %v", err)
+	}
+	select {
+	case pod := <-lines:
+		if pod != "web-2" {
+			t.Errorf("expected web-2, got %s", pod)
+		}
+	case <-ctx.Done():
+		t.Fatalf("new pod was not streamed")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+package kube
+
+import (
+	"context"
+	"sort"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// AccessRequest is the action whose permitted subjects are looked up.
+type AccessRequest struct {
+	Verb        string
+	Group       string
+	Resource    string
+	Subresource string
+	// Name restricts the check to a single object, rules limited by resourceNames only match it.
+	Name string
+	// Namespace is empty for cluster-scoped resources, in which case only ClusterRoleBindings apply.
+	Namespace string
+}
+
+// Grant is a subject allowed to perform the request, with the binding that grants it.
+type Grant struct {
+	SubjectKind      string `json:"subjectKind"`
+	SubjectName      string `json:"subjectName"`
+	SubjectNamespace string `json:"subjectNamespace,omitempty"`
+	BindingKind      string `json:"bindingKind"`
+	BindingName      string `json:"bindingName"`
+	RoleKind         string `json:"roleKind"`
+	RoleName         string `json:"roleName"`
+}
+
+// WhoCan evaluates the RBAC roles and bindings in the cluster and returns every
+// subject allowed to perform req. It doesn't account for other authorizers
+// (webhooks, node authorizer) or impersonation.
+func WhoCan(ctx context.Context, client kubernetes.Interface, req AccessRequest) ([]Grant, error) {
+	rbac := client.RbacV1()
+	var grants []Grant
+
+	// Roles are listed once and indexed, bindings usually outnumber them by far
+	clusterRoleList, err := rbac.ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errs.FromAPI(err, "listing cluster roles")
+	}
+	clusterRoles := make(map[string][]rbacv1.PolicyRule, len(clusterRoleList.Items))
+	for _, role := range clusterRoleList.Items {
+		clusterRoles[role.Name] = role.Rules
+	}
+
+	clusterBindings, err := rbac.ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errs.FromAPI(err, "listing cluster role bindings")
+	}
+	for _, binding := range clusterBindings.Items {
+		if rulesAllow(roleRules(binding.RoleRef, clusterRoles, nil), req) {
+			grants = append(grants, bindingGrants("ClusterRoleBinding", binding.Name, binding.RoleRef, binding.Subjects)...)
+		}
+	}
+
+	if req.Namespace != "" {
+		roleList, err := rbac.Roles(req.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, errs.FromAPI(err, "listing roles in %s", req.Namespace)
+		}
+		roles := make(map[string][]rbacv1.PolicyRule, len(roleList.Items))
+		for _, role := range roleList.Items {
+			roles[role.Name] = role.Rules
+		}
+
+		bindings, err := rbac.RoleBindings(req.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, errs.FromAPI(err, "listing role bindings in %s", req.Namespace)
+		}
+		for _, binding := range bindings.Items {
+			if rulesAllow(roleRules(binding.RoleRef, clusterRoles, roles), req) {
+				grants = append(grants, bindingGrants("RoleBinding", binding.Namespace+"/"+binding.Name, binding.RoleRef, binding.Subjects)...)
+			}
+		}
+	}
+
+	sort.SliceStable(grants, func(i, j int) bool {
+		if grants[i].SubjectKind != grants[j].SubjectKind {
+			return grants[i].SubjectKind < grants[j].SubjectKind
+		}
+		return grants[i].SubjectName < grants[j].SubjectName
+	})
+	return grants, nil
+}
+
+// roleRules returns the rules of the referenced role from the indexed roles,
+// nil if it doesn't exist (a dangling binding grants nothing).
+func roleRules(ref rbacv1.RoleRef, clusterRoles, roles map[string][]rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	switch ref.Kind {
+	case "ClusterRole":
+		return clusterRoles[ref.Name]
+	case "Role":
+		return roles[ref.Name]
+	}
+	return nil
+}
+
+func rulesAllow(rules []rbacv1.PolicyRule, req AccessRequest) bool {
+	for _, rule := range rules {
+		if ruleAllows(rule, req) {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleAllows mirrors the matching done by the RBAC authorizer for resource requests.
+func ruleAllows(rule rbacv1.PolicyRule, req AccessRequest) bool {
+	resource := req.Resource
+	if req.Subresource != "" {
+		resource += "/" + req.Subresource
+	}
+
+	if !matches(rule.Verbs, req.Verb, rbacv1.VerbAll) || !matches(rule.APIGroups, req.Group, rbacv1.APIGroupAll) {
+		return false
+	}
+
+	resourceMatched := false
+	for _, r := range rule.Resources {
+		if r == rbacv1.ResourceAll || r == resource ||
+			(req.Subresource != "" && r == "*/"+req.Subresource) {
+			resourceMatched = true
+			break
+		}
+	}
+	if !resourceMatched {
+		return false
+	}
+
+	if len(rule.ResourceNames) == 0 {
+		return true
+	}
+	// resource names have no wildcard
+	return req.Name != "" && matches(rule.ResourceNames, req.Name, "")
+}
+
+// matches compares case-sensitively like the authorizer; wildcard, when not
+// empty, matches any value.
+func matches(values []string, value, wildcard string) bool {
+	for _, v := range values {
+		if v == value || (wildcard != "" && v == wildcard) {
+			return true
+		}
+	}
+	return false
+}
+
+func bindingGrants(bindingKind, bindingName string, ref rbacv1.RoleRef, subjects []rbacv1.Subject) []Grant {
+	grants := make([]Grant, 0, len(subjects))
+	for _, subject := range subjects {
+		grants = append(grants, Grant{
+			SubjectKind:      subject.Kind,
+			SubjectName:      subject.Name,
+			SubjectNamespace: subject.Namespace,
+			BindingKind:      bindingKind,
+			BindingName:      bindingName,
+			RoleKind:         ref.Kind,
+			RoleName:         ref.Name,
+		})
+	}
+	return grants
+}
@@ -0,0 +1,93 @@
+package kube
+
+import (
+	"context"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRuleAllows(t *testing.T) {
+	deployments := AccessRequest{Verb: "get", Group: "apps", Resource: "deployments"}
+	tests := []struct {
+		name     string
+		rule     rbacv1.PolicyRule
+		req      AccessRequest
+		expected bool
+	}{
+		{"exact", rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}}, deployments, true},
+		{"wildcards", rbacv1.PolicyRule{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}, deployments, true},
+		{"other verb", rbacv1.PolicyRule{Verbs: []string{"list"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}}, deployments, false},
+		{"other group", rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"deployments"}}, deployments, false},
+		{"subresource only", rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"apps"}, Resources: []string{"deployments/scale"}}, deployments, false},
+		{"subresource", rbacv1.PolicyRule{Verbs: []string{"update"}, APIGroups: []string{"apps"}, Resources: []string{"deployments/scale"}},
+			AccessRequest{Verb: "update", Group: "apps", Resource: "deployments", Subresource: "scale"}, true},
+		{"resource names without name", rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}, ResourceNames: []string{"web"}}, deployments, false},
+		{"resource names with name", rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}, ResourceNames: []string{"web"}},
+			AccessRequest{Verb: "get", Group: "apps", Resource: "deployments", Name: "web"}, true},
+		{"mixed case verb", rbacv1.PolicyRule{Verbs: []string{"GET"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}}, deployments, false},
+		{"mixed case resource", rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"apps"}, Resources: []string{"Deployments"}}, deployments, false},
+		{"mixed case resource name", rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}, ResourceNames: []string{"Web"}},
+			AccessRequest{Verb: "get", Group: "apps", Resource: "deployments", Name: "web"}, false},
+		{"resource names wildcard", rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}, ResourceNames: []string{"*"}},
+			AccessRequest{Verb: "get", Group: "apps", Resource: "deployments", Name: "web"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := ruleAllows(tt.rule, tt.req); got != tt.expected {
+			t.Errorf("%s: expected %t, got %t", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestWhoCan(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "deployment-reader"},
+			Rules:      []rbacv1.PolicyRule{{Verbs: []string{"get", "list"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}}},
+		},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "readers"},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "deployment-reader"},
+			Subjects:   []rbacv1.Subject{{Kind: "Group", Name: "sre"}},
+		},
+		&rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod-reader", Namespace: "payments"},
+			Rules:      []rbacv1.PolicyRule{{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods"}}},
+		},
+		&rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod-readers", Namespace: "payments"},
+			RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "pod-reader"},
+			Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Name: "controller", Namespace: "payments"}},
+		},
+		&rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "deployers", Namespace: "payments"},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "deployment-reader"},
+			Subjects:   []rbacv1.Subject{{Kind: "User", Name: "alice"}},
+		},
+	)
+
+	grants, err := WhoCan(context.Background(), client, AccessRequest{Verb: "get", Group: "apps", Resource: "deployments", Namespace: "payments"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(grants) != 2 {
+		t.Fatalf("expected 2 grants, got %+v", grants)
+	}
+	if grants[0].SubjectKind != "Group" || grants[0].SubjectName != "sre" || grants[0].BindingKind != "ClusterRoleBinding" {
+		t.Errorf("unexpected first grant %+v", grants[0])
+	}
+	if grants[1].SubjectKind != "User" || grants[1].SubjectName != "alice" || grants[1].BindingName != "payments/deployers" {
+		t.Errorf("unexpected second grant %+v", grants[1])
+	}
+
+	// roles are listed once rather than fetched per binding
+	for _, action := range client.Actions() {
+		if action.GetVerb() != "list" {
+			t.Errorf("unexpected %s of %s", action.GetVerb(), action.GetResource().Resource)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package kube
+
+import (
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/restmapper"
+)
+
+// ResolveResource maps a resource name as typed by users ("deploy",
+// "deployments", "deployments.apps") to its GroupVersionResource using API
+// discovery, and reports whether the resource is namespaced.
+func ResolveResource(client discovery.DiscoveryInterface, resource string) (schema.GroupVersionResource, bool, error) {
+	groupResources, err := restmapper.GetAPIGroupResources(client)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, errs.Wrap(errs.CodeUnknown, err, "discovering API resources")
+	}
+	mapper := restmapper.NewShortcutExpander(restmapper.NewDiscoveryRESTMapper(groupResources), client, nil)
+
+	groupResource := schema.ParseGroupResource(resource)
+	gvr, err := mapper.ResourceFor(groupResource.WithVersion(""))
+	if err != nil {
+		return schema.GroupVersionResource{}, false, errs.Wrap(errs.CodeNotFound, err, "resolving resource %q", resource)
+	}
+
+	gvk, err := mapper.KindFor(gvr)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, errs.Wrap(errs.CodeNotFound, err, "resolving kind of %q", resource)
+	}
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, errs.Wrap(errs.CodeNotFound, err, "resolving scope of %q", resource)
+	}
+	return gvr, mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
+}
+
+// ResolveKind maps the group, version and kind of a manifest object to its
+// resource, and reports whether the resource is namespaced.
+func ResolveKind(client discovery.DiscoveryInterface, gvk schema.GroupVersionKind) (schema.GroupVersionResource, bool, error) {
+	groupResources, err := restmapper.GetAPIGroupResources(client)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, errs.Wrap(errs.CodeUnknown, err, "discovering API resources")
+	}
+	mapping, err := restmapper.NewDiscoveryRESTMapper(groupResources).RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, errs.Wrap(errs.CodeNotFound, err, "resolving %s", gvk)
+	}
+	return mapping.Resource, mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
+}
@@ -0,0 +1,79 @@
+package kube
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestResolveResource(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	discovery := client.Discovery().(*fakediscovery.FakeDiscovery)
+	discovery.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "nodes", Kind: "Node", Namespaced: false, Verbs: []string{"get", "list"}},
+			},
+		},
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "deployments", Kind: "Deployment", Namespaced: true, ShortNames: []string{"deploy"}, Verbs: []string{"get", "list"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		input      string
+		group      string
+		resource   string
+		namespaced bool
+	}{
+		{"deploy", "apps", "deployments", true},
+		{"deployments.apps", "apps", "deployments", true},
+		{"nodes", "", "nodes", false},
+	}
+	for _, tt := range tests {
+		gvr, namespaced, err := ResolveResource(discovery, tt.input)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if gvr.Group != tt.group || gvr.Resource != tt.resource || namespaced != tt.namespaced {
+			t.Errorf("%s: unexpected result %v namespaced=%t", tt.input, gvr, namespaced)
+		}
+	}
+
+	if _, _, err := ResolveResource(discovery, "widgets"); err == nil {
+		t.Errorf("expected error for unknown resource")
+	}
+}
+
+func TestResolveKind(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	discovery := client.Discovery().(*fakediscovery.FakeDiscovery)
+	discovery.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "deployments", Kind: "Deployment", Namespaced: true, Verbs: []string{"get", "list"}},
+			},
+		},
+	}
+
+	gvr, namespaced, err := ResolveKind(discovery, schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gvr.Resource != "deployments" || !namespaced {
+		t.Errorf("unexpected result %v namespaced=%t", gvr, namespaced)
+	}
+
+	if _, _, err := ResolveKind(discovery, schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}); err == nil {
+		t.Errorf("expected error for unknown kind")
+	}
+}
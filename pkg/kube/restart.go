@@ -0,0 +1,351 @@
+package kube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+// RestartedAtAnnotation is the pod template annotation kubectl rollout restart sets.
+const RestartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// RevisionAnnotation is the annotation the Deployment controller numbers
+// deployments and their ReplicaSets with.
+const RevisionAnnotation = "deployment.kubernetes.io/revision"
+
+// failingReasons are container waiting reasons a rollout doesn't recover from on its own.
+var failingReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+}
+
+// RestartDeployment triggers a rolling restart by stamping the pod template with
+// the restart time, like kubectl rollout restart. When maxUnavailable is set it
+// replaces the rolling update budget in the same patch.
+func RestartDeployment(ctx context.Context, client kubernetes.Interface, namespace, name string, maxUnavailable *intstr.IntOrString, now time.Time) (*appsv1.Deployment, error) {
+	deployments := client.AppsV1().Deployments(namespace)
+	current, err := deployments.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, errs.NotFound("deployment %s not found in namespace %s", name, namespace)
+	}
+	if err != nil {
+		return nil, errs.FromAPI(err, "getting deployment %s", name)
+	}
+	if current.Spec.Paused {
+		return nil, errs.Conflict("deployment %s is paused, resume it before restarting", name)
+	}
+
+	spec := map[string]interface{}{
+		"template": map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"annotations": map[string]string{RestartedAtAnnotation: now.Format(time.RFC3339)},
+			},
+		},
+	}
+	if maxUnavailable != nil {
+		if current.Spec.Strategy.Type == appsv1.RecreateDeploymentStrategyType {
+			return nil, errs.ValidationFailed("deployment %s uses the Recreate strategy, --max-unavailable doesn't apply", name)
+		}
+		spec["strategy"] = map[string]interface{}{
+			"rollingUpdate": map[string]interface{}{"maxUnavailable": maxUnavailable},
+		}
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{"spec": spec})
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeUnknown, err, "encoding restart patch")
+	}
+	deployment, err := deployments.Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return nil, errs.FromAPI(err, "patching deployment %s", name)
+	}
+	return deployment, nil
+}
+
+// RolloutStatus is the replica progress of a deployment rollout.
+type RolloutStatus struct {
+	Desired   int32
+	Total     int32
+	Updated   int32
+	Ready     int32
+	Available int32
+}
+
+// Complete reports whether every replica runs the new template and old ones are gone.
+func (s RolloutStatus) Complete() bool {
+	return s.Updated == s.Desired && s.Available == s.Desired && s.Total == s.Desired
+}
+
+func (s RolloutStatus) String() string {
+	return fmt.Sprintf("%d/%d updated, %d ready, %d available, %d total", s.Updated, s.Desired, s.Ready, s.Available, s.Total)
+}
+
+// PodState is a one-line summary of a pod during a rollout.
+type PodState struct {
+	Name     string
+	Phase    string
+	Ready    bool
+	Restarts int32
+	// Reason is the waiting reason of the first container not running, if any.
+	Reason string
+	// New is set for pods of the deployment's current pod template.
+	New bool
+}
+
+// Failing reports whether the pod failed or has a container stuck in a state it won't recover from.
+func (p PodState) Failing() bool {
+	return p.Phase == string(corev1.PodFailed) || failingReasons[p.Reason]
+}
+
+func (p PodState) String() string {
+	state := p.Phase
+	if p.Reason != "" {
+		state = p.Reason
+	}
+	if p.Ready {
+		state += ", ready"
+	}
+	return fmt.Sprintf("%s %s (restarts %d)", p.Name, state, p.Restarts)
+}
+
+// RolloutWatcher polls a deployment until its rollout completes, reporting
+// progress through OnStatus and OnPod as it changes.
+type RolloutWatcher struct {
+	Client    kubernetes.Interface
+	Namespace string
+	Name      string
+	Interval  time.Duration
+
+	OnStatus func(RolloutStatus)
+	// OnPod receives pods whose state changed; deleted pods are reported with Phase "Deleted".
+	OnPod func(PodState)
+}
+
+// Wait blocks until the rollout completes. It fails when a new pod is crashing or
+// can't start, when the deployment exceeds its progress deadline, or when ctx expires.
+func (w *RolloutWatcher) Wait(ctx context.Context) error {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastStatus *RolloutStatus
+	lastPods := map[string]PodState{}
+	for {
+		deployment, err := w.Client.AppsV1().Deployments(w.Namespace).Get(ctx, w.Name, metav1.GetOptions{})
+		if err != nil {
+			if ctx.Err() != nil {
+				return w.timeout(ctx, lastStatus)
+			}
+			return errs.FromAPI(err, "getting deployment %s", w.Name)
+		}
+		status := deploymentRolloutStatus(deployment)
+		if lastStatus == nil || *lastStatus != status {
+			lastStatus = &status
+			if w.OnStatus != nil {
+				w.OnStatus(status)
+			}
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+		if err != nil {
+			return errs.Wrap(errs.CodeValidationFailed, err, "invalid selector on deployment %s", w.Name)
+		}
+		pods, err := w.Client.CoreV1().Pods(w.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			if ctx.Err() != nil {
+				return w.timeout(ctx, lastStatus)
+			}
+			return errs.FromAPI(err, "listing pods of deployment %s", w.Name)
+		}
+		// The controller labels the pods of the current template with the hash of
+		// the new ReplicaSet, comparing labels doesn't depend on the client's clock
+		// like creation times do
+		current, err := newReplicaSet(ctx, w.Client, deployment, selector)
+		if err != nil {
+			if ctx.Err() != nil {
+				return w.timeout(ctx, lastStatus)
+			}
+			return err
+		}
+		templateHash := ""
+		if current != nil {
+			templateHash = current.Labels[appsv1.DefaultDeploymentUniqueLabelKey]
+		}
+		if err := w.checkPods(ctx, pods.Items, templateHash, lastPods); err != nil {
+			return err
+		}
+
+		if deployment.Status.ObservedGeneration >= deployment.Generation {
+			if status.Complete() {
+				return nil
+			}
+			for _, condition := range deployment.Status.Conditions {
+				if condition.Type == appsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+					return errs.Timeout("deployment %s exceeded its progress deadline: %s", w.Name, condition.Message)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return w.timeout(ctx, lastStatus)
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkPods reports pod changes since the previous poll and fails on the first
+// pod of the current template that is stuck.
+func (w *RolloutWatcher) checkPods(ctx context.Context, pods []corev1.Pod, templateHash string, last map[string]PodState) error {
+	seen := map[string]bool{}
+	for i := range pods {
+		pod := &pods[i]
+		state := podState(pod, templateHash)
+		seen[pod.Name] = true
+		if previous, ok := last[pod.Name]; !ok || previous != state {
+			last[pod.Name] = state
+			if w.OnPod != nil {
+				w.OnPod(state)
+			}
+		}
+		if state.New {
+			if err := w.podFailure(ctx, pod); err != nil {
+				return err
+			}
+		}
+	}
+	for name, state := range last {
+		if !seen[name] {
+			delete(last, name)
+			if w.OnPod != nil {
+				state.Phase, state.Reason, state.Ready = "Deleted", "", false
+				w.OnPod(state)
+			}
+		}
+	}
+	return nil
+}
+
+// podFailure returns a diagnostic error when pod failed or has a container stuck in a failing state.
+func (w *RolloutWatcher) podFailure(ctx context.Context, pod *corev1.Pod) error {
+	if pod.Status.Phase == corev1.PodFailed {
+		return errs.New(errs.CodeUnknown, "rollout of deployment %s failed: pod %s failed: %s %s", w.Name, pod.Name, pod.Status.Reason, pod.Status.Message)
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Waiting == nil || !failingReasons[status.State.Waiting.Reason] {
+			continue
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "rollout of deployment %s failed: pod %s container %s is in %s", w.Name, pod.Name, status.Name, status.State.Waiting.Reason)
+		if status.State.Waiting.Message != "" {
+			fmt.Fprintf(&b, ": %s", status.State.Waiting.Message)
+		}
+		if terminated := status.LastTerminationState.Terminated; terminated != nil {
+			fmt.Fprintf(&b, "\nlast termination: exit code %d, reason %s", terminated.ExitCode, terminated.Reason)
+			if logs := previousLogs(ctx, w.Client, pod.Namespace, pod.Name, status.Name); logs != "" {
+				fmt.Fprintf(&b, "\nlast log lines:\n%s", logs)
+			}
+		}
+		return errs.New(errs.CodeUnknown, "%s", b.String())
+	}
+	return nil
+}
+
+// timeout describes how far the rollout got when ctx expired.
+func (w *RolloutWatcher) timeout(ctx context.Context, status *RolloutStatus) error {
+	if status == nil {
+		return errs.Wrap(errs.CodeTimeout, ctx.Err(), "waiting for deployment %s", w.Name)
+	}
+	return errs.Wrap(errs.CodeTimeout, ctx.Err(), "waiting for deployment %s (%s)", w.Name, status)
+}
+
+func deploymentRolloutStatus(deployment *appsv1.Deployment) RolloutStatus {
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+	return RolloutStatus{
+		Desired:   desired,
+		Total:     deployment.Status.Replicas,
+		Updated:   deployment.Status.UpdatedReplicas,
+		Ready:     deployment.Status.ReadyReplicas,
+		Available: deployment.Status.AvailableReplicas,
+	}
+}
+
+// newReplicaSet returns the ReplicaSet of the deployment's current revision, nil
+// until the controller has created it.
+func newReplicaSet(ctx context.Context, client kubernetes.Interface, deployment *appsv1.Deployment, selector labels.Selector) (*appsv1.ReplicaSet, error) {
+	revision, ok := deployment.Annotations[RevisionAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	replicaSets, err := client.AppsV1().ReplicaSets(deployment.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, errs.FromAPI(err, "listing replica sets of deployment %s", deployment.Name)
+	}
+	for i := range replicaSets.Items {
+		rs := &replicaSets.Items[i]
+		if metav1.IsControlledBy(rs, deployment) && rs.Annotations[RevisionAnnotation] == revision {
+			return rs, nil
+		}
+	}
+	return nil, nil
+}
+
+func podState(pod *corev1.Pod, templateHash string) PodState {
+	state := PodState{
+		Name:  pod.Name,
+		Phase: string(pod.Status.Phase),
+		New:   templateHash != "" && pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey] == templateHash,
+	}
+	if pod.DeletionTimestamp != nil {
+		state.Phase = "Terminating"
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			state.Ready = condition.Status == corev1.ConditionTrue
+		}
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		state.Restarts += status.RestartCount
+		if state.Reason == "" && status.State.Waiting != nil {
+			state.Reason = status.State.Waiting.Reason
+		}
+	}
+	return state
+}
+
+// previousLogs returns the last lines of the previous instance of a container, best effort.
+func previousLogs(ctx context.Context, client kubernetes.Interface, namespace, pod, container string) string {
+	tail := int64(10)
+	data, err := client.CoreV1().Pods(namespace).GetLogs(pod, &corev1.PodLogOptions{
+		Container: container,
+		Previous:  true,
+		TailLines: &tail,
+	}).DoRaw(ctx)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(string(data), "\n")
+}
@@ -0,0 +1,207 @@
+package kube
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/revision"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testDeployment(replicas int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "web",
+			Namespace:   "default",
+			UID:         types.UID("web-uid"),
+			Generation:  2,
+			Annotations: map[string]string{RevisionAnnotation: "2"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Strategy: appsv1.DeploymentStrategy{Type: appsv1.RollingUpdateDeploymentStrategyType},
+		},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 2,
+			Replicas:           replicas,
+			UpdatedReplicas:    replicas,
+			ReadyReplicas:      replicas,
+			AvailableReplicas:  replicas,
+		},
+	}
+}
+
+func TestRestartDeployment(t *testing.T) {
+	client := fake.NewSimpleClientset(testDeployment(3))
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	maxUnavailable := intstr.FromInt32(1)
+
+	deployment, err := RestartDeployment(context.Background(), client, "default", "web", &maxUnavailable, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := deployment.Spec.Template.Annotations[RestartedAtAnnotation]; got != "2024-05-01T10:00:00Z" {
+		t.Errorf("unexpected restart annotation %q", got)
+	}
+	if deployment.Spec.Strategy.RollingUpdate == nil || deployment.Spec.Strategy.RollingUpdate.MaxUnavailable.IntValue() != 1 {
+		t.Errorf("expected maxUnavailable 1, got %+v", deployment.Spec.Strategy.RollingUpdate)
+	}
+
+	if _, err := RestartDeployment(context.Background(), client, "default", "api", nil, now); !errs.IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestRolloutWatcherCompletes(t *testing.T) {
+	client := fake.NewSimpleClientset(testDeployment(2))
+	var statuses []RolloutStatus
+	watcher := &RolloutWatcher{
+		Client:    client,
+		Namespace: "default",
+		Name:      "web",
+		Interval:  time.Millisecond,
+		OnStatus:  func(s RolloutStatus) { statuses = append(statuses, s) },
+	}
+	if err := watcher.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(statuses) != 1 || !statuses[0].Complete() {
+		t.Errorf("expected one complete status, got %+v", statuses)
+	}
+}
+
+func TestRolloutWatcherReportsCrashingPods(t *testing.T) {
+	deployment := testDeployment(2)
+	deployment.Status.UpdatedReplicas = 1
+	// the API server's clock is behind, the new pod looks older than the restart
+	pod := crashingPod("web-new", "new", time.Now().Add(-time.Hour))
+	client := fake.NewSimpleClientset(deployment, testReplicaSet(deployment, "new", "2"), pod)
+
+	var pods []PodState
+	watcher := &RolloutWatcher{
+		Client:    client,
+		Namespace: "default",
+		Name:      "web",
+		Interval:  time.Millisecond,
+		OnPod:     func(p PodState) { pods = append(pods, p) },
+	}
+	err := watcher.Wait(context.Background())
+	if err == nil {
+		t.Fatalf("expected rollout failure")
+	}
+	for _, want := range []string{"web-new", "CrashLoopBackOff", "exit code 1", "fake logs"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got %v", want, err)
+		}
+	}
+	if len(pods) != 1 || !pods[0].New || pods[0].Reason != "CrashLoopBackOff" {
+		t.Errorf("unexpected pod states %+v", pods)
+	}
+}
+
+func TestRolloutWatcherIgnoresOldTemplatePods(t *testing.T) {
+	// the API server's clock is ahead, the old pod looks newer than the restart
+	deployment := testDeployment(2)
+	client := fake.NewSimpleClientset(deployment,
+		testReplicaSet(deployment, "new", "2"),
+		testReplicaSet(deployment, "old", "1"),
+		crashingPod("web-old", "old", time.Now().Add(time.Hour)))
+
+	watcher := &RolloutWatcher{Client: client, Namespace: "default", Name: "web", Interval: time.Millisecond}
+	if err := watcher.Wait(context.Background()); err != nil {
+		t.Errorf("expected pods of the previous template to be ignored, got %v", err)
+	}
+}
+
+func TestRolloutWatcherUsesReplicaSetHash(t *testing.T) {
+	// the API server defaults fields the local template leaves out, so the hash
+	// computed from it matches no ReplicaSet
+	deployment := testDeployment(2)
+	deployment.Status.UpdatedReplicas = 1
+	localHash := revision.PodTemplateHash(&deployment.Spec.Template, nil)
+	client := fake.NewSimpleClientset(deployment,
+		testReplicaSet(deployment, "server", "2"),
+		crashingPod("web-local", localHash, time.Now()),
+		crashingPod("web-new", "server", time.Now()))
+
+	var pods []PodState
+	watcher := &RolloutWatcher{
+		Client:    client,
+		Namespace: "default",
+		Name:      "web",
+		Interval:  time.Millisecond,
+		OnPod:     func(p PodState) { pods = append(pods, p) },
+	}
+	err := watcher.Wait(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "web-new") {
+		t.Fatalf("expected the pod of the new ReplicaSet to fail the rollout, got %v", err)
+	}
+	for _, p := range pods {
+		if p.New != (p.Name == "web-new") {
+			t.Errorf("unexpected New for %+v", p)
+		}
+	}
+}
+
+func TestRolloutWatcherTimeout(t *testing.T) {
+	deployment := testDeployment(2)
+	deployment.Status.UpdatedReplicas = 1
+	client := fake.NewSimpleClientset(deployment)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	watcher := &RolloutWatcher{Client: client, Namespace: "default", Name: "web", Interval: time.Millisecond}
+	if err := watcher.Wait(ctx); !errs.IsTimeout(err) {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+}
+
+func crashingPod(name, templateHash string, created time.Time) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			Labels:            map[string]string{"app": "web", appsv1.DefaultDeploymentUniqueLabelKey: templateHash},
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "app",
+				RestartCount: 3,
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+					Reason:  "CrashLoopBackOff",
+					Message: "back-off 40s restarting failed container",
+				}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}},
+			}},
+		},
+	}
+}
+
+// testReplicaSet returns a ReplicaSet of deployment with the given pod-template-hash and revision.
+func testReplicaSet(deployment *appsv1.Deployment, templateHash, revision string) *appsv1.ReplicaSet {
+	controller := true
+	return &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Name:        deployment.Name + "-" + templateHash,
+		Namespace:   deployment.Namespace,
+		Labels:      map[string]string{"app": "web", appsv1.DefaultDeploymentUniqueLabelKey: templateHash},
+		Annotations: map[string]string{RevisionAnnotation: revision},
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       deployment.Name,
+			UID:        deployment.UID,
+			Controller: &controller,
+		}},
+	}}
+}
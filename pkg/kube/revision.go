@@ -0,0 +1,56 @@
+package kube
+
+import (
+	"context"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/revision"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// TemplateRevision ties a deployment's current pod template to its ReplicaSet.
+type TemplateRevision struct {
+	// Hash is the pod-template-hash computed from the deployment's current template.
+	Hash string `json:"hash"`
+	// ReplicaSet is the ReplicaSet of the deployment's current revision, empty
+	// when the controller hasn't created it yet.
+	ReplicaSet string `json:"replicaSet,omitempty"`
+	// ReplicaSetHash is the pod-template-hash label of that ReplicaSet. It differs
+	// from Hash when the template hashed by the controller doesn't match the one
+	// read back from the API server.
+	ReplicaSetHash string `json:"replicaSetHash,omitempty"`
+	// Revision is the deployment.kubernetes.io/revision of that ReplicaSet.
+	Revision string `json:"revision,omitempty"`
+}
+
+// DeploymentTemplateRevision computes the pod-template-hash of a deployment as
+// the Deployment controller does and finds the ReplicaSet of its current revision.
+func DeploymentTemplateRevision(ctx context.Context, client kubernetes.Interface, namespace, name string) (TemplateRevision, error) {
+	deployment, err := client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return TemplateRevision{}, errs.NotFound("deployment %s not found in namespace %s", name, namespace)
+	}
+	if err != nil {
+		return TemplateRevision{}, errs.FromAPI(err, "getting deployment %s", name)
+	}
+
+	result := TemplateRevision{Hash: revision.PodTemplateHash(&deployment.Spec.Template, deployment.Status.CollisionCount)}
+
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return result, errs.Wrap(errs.CodeValidationFailed, err, "invalid selector on deployment %s", name)
+	}
+	current, err := newReplicaSet(ctx, client, deployment, selector)
+	if err != nil {
+		return result, err
+	}
+	if current != nil {
+		result.ReplicaSet = current.Name
+		result.ReplicaSetHash = current.Labels[appsv1.DefaultDeploymentUniqueLabelKey]
+		result.Revision = current.Annotations[RevisionAnnotation]
+	}
+	return result, nil
+}
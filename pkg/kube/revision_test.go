@@ -0,0 +1,69 @@
+package kube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/revision"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDeploymentTemplateRevision(t *testing.T) {
+	deployment := testDeployment(2)
+	deployment.Annotations[RevisionAnnotation] = "3"
+	deployment.Spec.Template = corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Image: "nginx:1.27"}}},
+	}
+	hash := revision.PodTemplateHash(&deployment.Spec.Template, nil)
+	client := fake.NewSimpleClientset(deployment,
+		testReplicaSet(deployment, hash, "3"),
+		testReplicaSet(deployment, "old", "2"))
+
+	result, err := DeploymentTemplateRevision(context.Background(), client, "default", "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := TemplateRevision{Hash: hash, ReplicaSet: "web-" + hash, ReplicaSetHash: hash, Revision: "3"}
+	if result != expected {
+		t.Errorf("expected %+v, got %+v", expected, result)
+	}
+}
+
+func TestDeploymentTemplateRevisionReportsLiveHash(t *testing.T) {
+	// the API server defaulted fields, the controller hashed a different template
+	deployment := testDeployment(2)
+	client := fake.NewSimpleClientset(deployment, testReplicaSet(deployment, "server", "2"))
+
+	result, err := DeploymentTemplateRevision(context.Background(), client, "default", "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ReplicaSet != "web-server" || result.ReplicaSetHash != "server" || result.Hash == "server" {
+		t.Errorf("expected the ReplicaSet of the current revision with its own hash, got %+v", result)
+	}
+}
+
+func TestDeploymentTemplateRevisionWithoutReplicaSet(t *testing.T) {
+	client := fake.NewSimpleClientset(testDeployment(1))
+
+	result, err := DeploymentTemplateRevision(context.Background(), client, "default", "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Hash == "" || result.ReplicaSet != "" {
+		t.Errorf("expected only a hash, got %+v", result)
+	}
+}
+
+func TestDeploymentTemplateRevisionNotFound(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	_, err := DeploymentTemplateRevision(context.Background(), client, "default", "web")
+	if !errs.IsNotFound(err) {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ServiceAccountOptions describe the service account a kubeconfig is generated for.
+type ServiceAccountOptions struct {
+	Namespace string
+	Name      string
+	// Create creates the service account if it doesn't exist yet.
+	Create bool
+	// LongLived uses a service-account-token Secret instead of a time-bound TokenRequest.
+	LongLived bool
+	// Duration is the requested lifetime of a TokenRequest token.
+	Duration time.Duration
+	// ClusterName names the cluster entry in the generated kubeconfig.
+	ClusterName string
+}
+
+// ServiceAccountKubeconfig returns a standalone kubeconfig authenticating as the
+// service account, pointing at the server and CA of restConfig.
+func ServiceAccountKubeconfig(ctx context.Context, client kubernetes.Interface, restConfig *rest.Config, opts ServiceAccountOptions) (*clientcmdapi.Config, error) {
+	if err := ensureServiceAccount(ctx, client, opts); err != nil {
+		return nil, err
+	}
+
+	var token string
+	var err error
+	if opts.LongLived {
+		token, err = secretToken(ctx, client, opts)
+	} else {
+		token, err = requestToken(ctx, client, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ca, err := clusterCA(ctx, client, restConfig, opts.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	user := opts.Namespace + "-" + opts.Name
+	contextName := user + "@" + opts.ClusterName
+	config := clientcmdapi.NewConfig()
+	config.Clusters[opts.ClusterName] = &clientcmdapi.Cluster{
+		Server:                   restConfig.Host,
+		CertificateAuthorityData: ca,
+	}
+	config.AuthInfos[user] = &clientcmdapi.AuthInfo{Token: token}
+	config.Contexts[contextName] = &clientcmdapi.Context{
+		Cluster:   opts.ClusterName,
+		AuthInfo:  user,
+		Namespace: opts.Namespace,
+	}
+	config.CurrentContext = contextName
+	return config, nil
+}
+
+func ensureServiceAccount(ctx context.Context, client kubernetes.Interface, opts ServiceAccountOptions) error {
+	_, err := client.CoreV1().ServiceAccounts(opts.Namespace).Get(ctx, opts.Name, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		return nil
+	case !apierrors.IsNotFound(err):
+		return errs.FromAPI(err, "getting service account %s/%s", opts.Namespace, opts.Name)
+	case !opts.Create:
+		return errs.NotFound("service account %s/%s not found, pass --create to create it", opts.Namespace, opts.Name)
+	}
+
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: opts.Name, Namespace: opts.Namespace}}
+	if _, err := client.CoreV1().ServiceAccounts(opts.Namespace).Create(ctx, sa, metav1.CreateOptions{}); err != nil {
+		return errs.FromAPI(err, "creating service account %s/%s", opts.Namespace, opts.Name)
+	}
+	return nil
+}
+
+func requestToken(ctx context.Context, client kubernetes.Interface, opts ServiceAccountOptions) (string, error) {
+	seconds := int64(opts.Duration.Seconds())
+	request := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: &seconds},
+	}
+	response, err := client.CoreV1().ServiceAccounts(opts.Namespace).CreateToken(ctx, opts.Name, request, metav1.CreateOptions{})
+	if err != nil {
+		return "", errs.FromAPI(err, "requesting token for %s/%s", opts.Namespace, opts.Name)
+	}
+	return response.Status.Token, nil
+}
+
+// secretToken creates (or reuses) a service-account-token Secret and waits for
+// the token controller to populate it.
+func secretToken(ctx context.Context, client kubernetes.Interface, opts ServiceAccountOptions) (string, error) {
+	secrets := client.CoreV1().Secrets(opts.Namespace)
+	name := opts.Name + "-token"
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   opts.Namespace,
+			Annotations: map[string]string{corev1.ServiceAccountNameKey: opts.Name},
+		},
+		Type: corev1.SecretTypeServiceAccountToken,
+	}
+	_, err := secrets.Create(ctx, secret, metav1.CreateOptions{})
+	switch {
+	case apierrors.IsAlreadyExists(err):
+		if err := checkTokenSecret(ctx, client, opts, name); err != nil {
+			return "", err
+		}
+	case err != nil:
+		return "", errs.FromAPI(err, "creating token secret %s/%s", opts.Namespace, name)
+	}
+
+	var token string
+	err = wait.PollUntilContextTimeout(ctx, 500*time.Millisecond, 30*time.Second, true, func(ctx context.Context) (bool, error) {
+		current, err := secrets.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, errs.FromAPI(err, "getting token secret %s/%s", opts.Namespace, name)
+		}
+		token = string(current.Data[corev1.ServiceAccountTokenKey])
+		return token != "", nil
+	})
+	if wait.Interrupted(err) && !errors.Is(err, context.Canceled) {
+		return "", errs.Wrap(errs.CodeTimeout, err, "waiting for token in secret %s/%s", opts.Namespace, name)
+	}
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// checkTokenSecret makes sure an existing Secret of the expected name is a
+// token Secret of this service account, so another account's credentials are
+// never put into the kubeconfig.
+func checkTokenSecret(ctx context.Context, client kubernetes.Interface, opts ServiceAccountOptions, name string) error {
+	existing, err := client.CoreV1().Secrets(opts.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errs.FromAPI(err, "getting token secret %s/%s", opts.Namespace, name)
+	}
+	if existing.Type != corev1.SecretTypeServiceAccountToken {
+		return errs.Conflict("secret %s/%s already exists with type %s, not %s", opts.Namespace, name, existing.Type, corev1.SecretTypeServiceAccountToken)
+	}
+	if owner := existing.Annotations[corev1.ServiceAccountNameKey]; owner != opts.Name {
+		return errs.Conflict("secret %s/%s already exists for service account %q, not %q", opts.Namespace, name, owner, opts.Name)
+	}
+	return nil
+}
+
+// clusterCA returns the CA bundle from the client configuration, falling back
+// to the kube-root-ca.crt ConfigMap published in every namespace.
+func clusterCA(ctx context.Context, client kubernetes.Interface, restConfig *rest.Config, namespace string) ([]byte, error) {
+	if len(restConfig.CAData) > 0 {
+		return restConfig.CAData, nil
+	}
+	if restConfig.CAFile != "" {
+		data, err := os.ReadFile(restConfig.CAFile)
+		if os.IsNotExist(err) {
+			return nil, errs.Wrap(errs.CodeNotFound, err, "reading cluster CA")
+		}
+		if err != nil {
+			return nil, errs.Wrap(errs.CodeUnknown, err, "reading cluster CA")
+		}
+		return data, nil
+	}
+
+	configMap, err := client.CoreV1().ConfigMaps(namespace).Get(ctx, "kube-root-ca.crt", metav1.GetOptions{})
+	if err != nil {
+		return nil, errs.FromAPI(err, "getting cluster CA from kube-root-ca.crt")
+	}
+	ca := configMap.Data["ca.crt"]
+	if ca == "" {
+		return nil, errs.NotFound("no ca.crt in ConfigMap %s/kube-root-ca.crt", namespace)
+	}
+	return []byte(ca), nil
+}
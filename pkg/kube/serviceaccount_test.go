@@ -0,0 +1,149 @@
+package kube
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestServiceAccountKubeconfig(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-root-ca.crt", Namespace: "ci"},
+		Data:       map[string]string{"ca.crt": "test-ca"},
+	})
+	client.PrependReactor("create", "serviceaccounts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "token" {
+			return false, nil, nil
+		}
+		return true, &authenticationv1.TokenRequest{Status: authenticationv1.TokenRequestStatus{Token: "test-token"}}, nil
+	})
+
+	opts := ServiceAccountOptions{
+		Namespace:   "ci",
+		Name:        "deployer",
+		Create:      true,
+		Duration:    time.Hour,
+		ClusterName: "prod",
+	}
+	config, err := ServiceAccountKubeconfig(context.Background(), client, &rest.Config{Host: "https://prod.example.com"}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := client.CoreV1().ServiceAccounts("ci").Get(context.Background(), "deployer", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected service account to be created: %v", err)
+	}
+	cluster := config.Clusters["prod"]
+	if cluster.Server != "https://prod.example.com" || string(cluster.CertificateAuthorityData) != "test-ca" {
+		t.Errorf("unexpected cluster entry %+v", cluster)
+	}
+	current := config.Contexts[config.CurrentContext]
+	if current.Namespace != "ci" || config.AuthInfos[current.AuthInfo].Token != "test-token" {
+		t.Errorf("unexpected context %+v", current)
+	}
+}
+
+func TestServiceAccountKubeconfigMissingAccount(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	opts := ServiceAccountOptions{Namespace: "ci", Name: "deployer", Duration: time.Hour, ClusterName: "prod"}
+
+	_, err := ServiceAccountKubeconfig(context.Background(), client, &rest.Config{}, opts)
+	if !errs.IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func tokenSecretOptions() ServiceAccountOptions {
+	return ServiceAccountOptions{Namespace: "ci", Name: "deployer", LongLived: true, ClusterName: "prod"}
+}
+
+func TestServiceAccountKubeconfigReusesTokenSecret(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "deployer", Namespace: "ci"}},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "deployer-token", Namespace: "ci", Annotations: map[string]string{corev1.ServiceAccountNameKey: "deployer"}},
+			Type:       corev1.SecretTypeServiceAccountToken,
+			Data:       map[string][]byte{corev1.ServiceAccountTokenKey: []byte("secret-token")},
+		},
+	)
+
+	config, err := ServiceAccountKubeconfig(context.Background(), client, &rest.Config{TLSClientConfig: rest.TLSClientConfig{CAData: []byte("test-ca")}}, tokenSecretOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	current := config.Contexts[config.CurrentContext]
+	if token := config.AuthInfos[current.AuthInfo].Token; token != "secret-token" {
+		t.Errorf("expected the existing secret's token, got %q", token)
+	}
+}
+
+func TestServiceAccountKubeconfigRejectsForeignSecret(t *testing.T) {
+	tests := map[string]*corev1.Secret{
+		"other service account": {
+			ObjectMeta: metav1.ObjectMeta{Name: "deployer-token", Namespace: "ci", Annotations: map[string]string{corev1.ServiceAccountNameKey: "admin"}},
+			Type:       corev1.SecretTypeServiceAccountToken,
+			Data:       map[string][]byte{corev1.ServiceAccountTokenKey: []byte("admin-token")},
+		},
+		"opaque secret": {
+			ObjectMeta: metav1.ObjectMeta{Name: "deployer-token", Namespace: "ci", Annotations: map[string]string{corev1.ServiceAccountNameKey: "deployer"}},
+			Type:       corev1.SecretTypeOpaque,
+			Data:       map[string][]byte{corev1.ServiceAccountTokenKey: []byte("other-token")},
+		},
+	}
+	for name, secret := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "deployer", Namespace: "ci"}}, secret)
+
+			_, err := ServiceAccountKubeconfig(context.Background(), client, &rest.Config{TLSClientConfig: rest.TLSClientConfig{CAData: []byte("test-ca")}}, tokenSecretOptions())
+			if !errs.IsConflict(err) {
+				t.Errorf("expected conflict error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestServiceAccountKubeconfigSecretGetError(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "deployer", Namespace: "ci"}})
+	client.PrependReactor("get", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewNotFound(corev1.Resource("secrets"), "deployer-token")
+	})
+
+	_, err := ServiceAccountKubeconfig(context.Background(), client, &rest.Config{TLSClientConfig: rest.TLSClientConfig{CAData: []byte("test-ca")}}, tokenSecretOptions())
+	if !errs.IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestClusterCAErrors(t *testing.T) {
+	dir := t.TempDir()
+	emptyConfigMap := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-root-ca.crt", Namespace: "ci"},
+	})
+	tests := []struct {
+		name     string
+		config   *rest.Config
+		expected errs.Code
+	}{
+		{"empty ConfigMap", &rest.Config{}, errs.CodeNotFound},
+		{"missing CA file", &rest.Config{TLSClientConfig: rest.TLSClientConfig{CAFile: filepath.Join(dir, "missing.crt")}}, errs.CodeNotFound},
+		{"unreadable CA file", &rest.Config{TLSClientConfig: rest.TLSClientConfig{CAFile: dir}}, errs.CodeUnknown},
+	}
+
+	for _, tt := range tests {
+		_, err := clusterCA(context.Background(), emptyConfigMap, tt.config, "ci")
+		if err == nil || errs.CodeOf(err) != tt.expected {
+			t.Errorf("%s: expected %s error, got %v", tt.name, tt.expected, err)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package kube
+
+import (
+	"context"
+	"strings"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Workload identifies a pod controller by kind and name, e.g. deployment/web.
+type Workload struct {
+	Kind string
+	Name string
+}
+
+func (w Workload) String() string {
+	return w.Kind + "/" + w.Name
+}
+
+// workloadKinds maps accepted spellings to canonical workload kinds.
+var workloadKinds = map[string]string{
+	"deployment":   "deployment",
+	"deployments":  "deployment",
+	"deploy":       "deployment",
+	"statefulset":  "statefulset",
+	"statefulsets": "statefulset",
+	"sts":          "statefulset",
+	"daemonset":    "daemonset",
+	"daemonsets":   "daemonset",
+	"ds":           "daemonset",
+}
+
+// ParseWorkload parses "kind/name" references such as deployment/web or sts/db.
+func ParseWorkload(ref string) (Workload, error) {
+	kind, name, found := strings.Cut(ref, "/")
+	if !found || name == "" {
+		return Workload{}, errs.ValidationFailed("expected <kind>/<name>, got %q", ref)
+	}
+	canonical, ok := workloadKinds[strings.ToLower(kind)]
+	if !ok {
+		return Workload{}, errs.ValidationFailed("unsupported workload kind %q, use deployment, statefulset or daemonset", kind)
+	}
+	return Workload{Kind: canonical, Name: name}, nil
+}
+
+// PodSelector returns the label selector of the workload's pods.
+func PodSelector(ctx context.Context, client kubernetes.Interface, namespace string, workload Workload) (labels.Selector, error) {
+	var selector *metav1.LabelSelector
+	var err error
+	apps := client.AppsV1()
+	switch workload.Kind {
+	case "deployment":
+		var d *appsv1.Deployment
+		if d, err = apps.Deployments(namespace).Get(ctx, workload.Name, metav1.GetOptions{}); err == nil {
+			selector = d.Spec.Selector
+		}
+	case "statefulset":
+		var s *appsv1.StatefulSet
+		if s, err = apps.StatefulSets(namespace).Get(ctx, workload.Name, metav1.GetOptions{}); err == nil {
+			selector = s.Spec.Selector
+		}
+	case "daemonset":
+		var d *appsv1.DaemonSet
+		if d, err = apps.DaemonSets(namespace).Get(ctx, workload.Name, metav1.GetOptions{}); err == nil {
+			selector = d.Spec.Selector
+		}
+	default:
+		return nil, errs.ValidationFailed("unsupported workload kind %q", workload.Kind)
+	}
+
+	if apierrors.IsNotFound(err) {
+		return nil, errs.NotFound("%s not found in namespace %s", workload, namespace)
+	}
+	if err != nil {
+		return nil, errs.FromAPI(err, "getting %s", workload)
+	}
+
+	result, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeValidationFailed, err, "invalid selector on %s", workload)
+	}
+	if result.Empty() {
+		return nil, errs.ValidationFailed("%s has an empty selector", workload)
+	}
+	return result, nil
+}
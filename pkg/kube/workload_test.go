@@ -0,0 +1,60 @@
+package kube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseWorkload(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Workload
+	}{
+		{"deployment/web", Workload{Kind: "deployment", Name: "web"}},
+		{"deploy/web", Workload{Kind: "deployment", Name: "web"}},
+		{"STS/db", Workload{Kind: "statefulset", Name: "db"}},
+		{"ds/agent", Workload{Kind: "daemonset", Name: "agent"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseWorkload(tt.input)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.input, tt.want, got)
+		}
+	}
+
+	for _, input := range []string{"web", "deployment/", "job/web"} {
+		if _, err := ParseWorkload(input); !errs.IsValidationFailed(err) {
+			t.Errorf("%s: expected validation error, got %v", input, err)
+		}
+	}
+}
+
+func TestPodSelector(t *testing.T) {
+	client := fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		},
+	})
+
+	selector, err := PodSelector(context.Background(), client, "default", Workload{Kind: "deployment", Name: "web"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if selector.String() != "app=web" {
+		t.Errorf("expected app=web, got %s", selector)
+	}
+
+	if _, err := PodSelector(context.Background(), client, "default", Workload{Kind: "deployment", Name: "api"}); !errs.IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
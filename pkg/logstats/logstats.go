@@ -0,0 +1,145 @@
+// Package logstats classifies log lines by level and summarizes error rates, so
+// a rollout can be sanity-checked from its logs without a log backend.
+package logstats
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Levels reported by the analyzer. Lines matching no rule are LevelOther.
+const (
+	LevelError = "error"
+	LevelWarn  = "warn"
+	LevelOther = "other"
+)
+
+// Rule assigns Level to lines matching Pattern. Rules are evaluated in order.
+type Rule struct {
+	Level   string
+	Pattern *regexp.Regexp
+}
+
+// DefaultRules recognize plain-text, logfmt and JSON level markers.
+var DefaultRules = []Rule{
+	{Level: LevelError, Pattern: regexp.MustCompile(`(?i)\b(error|fatal|panic|exception)\b|level=(error|fatal)|"level":\s*"(error|fatal)"|^E\d{4} `)},
+	{Level: LevelWarn, Pattern: regexp.MustCompile(`(?i)\bwarn(ing)?\b|level=warn|"level":\s*"warn(ing)?"|^W\d{4} `)},
+}
+
+// variablePattern matches the parts of a message that differ between otherwise
+// identical lines: timestamps, UUIDs, hex ids and numbers.
+var variablePattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ][\d:.]+Z?|[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}|\b0x[0-9a-fA-F]+\b|\b[0-9a-f]{12,}\b|\d+`)
+
+// MessageCount is a normalized error message and how often it occurred.
+type MessageCount struct {
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+// SourceCount is the number of lines and errors from a single source, e.g. a pod.
+type SourceCount struct {
+	Source string `json:"source"`
+	Lines  int    `json:"lines"`
+	Errors int    `json:"errors"`
+}
+
+// Summary is the result of an analysis.
+type Summary struct {
+	Lines       int            `json:"lines"`
+	Levels      map[string]int `json:"levels"`
+	ErrorRate   float64        `json:"errorRate"`
+	Sources     []SourceCount  `json:"sources"`
+	TopMessages []MessageCount `json:"topMessages"`
+}
+
+// Analyzer accumulates line counts. It is not safe for concurrent use.
+type Analyzer struct {
+	rules    []Rule
+	lines    int
+	levels   map[string]int
+	sources  map[string]*SourceCount
+	messages map[string]int
+}
+
+// New returns an analyzer using rules, or DefaultRules when rules is empty.
+func New(rules []Rule) *Analyzer {
+	if len(rules) == 0 {
+		rules = DefaultRules
+	}
+	return &Analyzer{
+		rules:    rules,
+		levels:   map[string]int{},
+		sources:  map[string]*SourceCount{},
+		messages: map[string]int{},
+	}
+}
+
+// Classify returns the level of the first rule matching line.
+func (a *Analyzer) Classify(line string) string {
+	for _, rule := range a.rules {
+		if rule.Pattern.MatchString(line) {
+			return rule.Level
+		}
+	}
+	return LevelOther
+}
+
+// Add records a line emitted by source.
+func (a *Analyzer) Add(source, line string) {
+	level := a.Classify(line)
+	a.lines++
+	a.levels[level]++
+
+	count, ok := a.sources[source]
+	if !ok {
+		count = &SourceCount{Source: source}
+		a.sources[source] = count
+	}
+	count.Lines++
+
+	if level == LevelError {
+		count.Errors++
+		a.messages[Normalize(line)]++
+	}
+}
+
+// Summary returns the totals so far with at most top error messages, most frequent first.
+func (a *Analyzer) Summary(top int) Summary {
+	summary := Summary{Lines: a.lines, Levels: map[string]int{}}
+	for level, n := range a.levels {
+		summary.Levels[level] = n
+	}
+	if a.lines > 0 {
+		summary.ErrorRate = float64(a.levels[LevelError]) / float64(a.lines)
+	}
+
+	for _, count := range a.sources {
+		summary.Sources = append(summary.Sources, *count)
+	}
+	sort.Slice(summary.Sources, func(i, j int) bool {
+		if summary.Sources[i].Errors != summary.Sources[j].Errors {
+			return summary.Sources[i].Errors > summary.Sources[j].Errors
+		}
+		return summary.Sources[i].Source < summary.Sources[j].Source
+	})
+
+	for message, n := range a.messages {
+		summary.TopMessages = append(summary.TopMessages, MessageCount{Message: message, Count: n})
+	}
+	sort.Slice(summary.TopMessages, func(i, j int) bool {
+		if summary.TopMessages[i].Count != summary.TopMessages[j].Count {
+			return summary.TopMessages[i].Count > summary.TopMessages[j].Count
+		}
+		return summary.TopMessages[i].Message < summary.TopMessages[j].Message
+	})
+	if top >= 0 && len(summary.TopMessages) > top {
+		summary.TopMessages = summary.TopMessages[:top]
+	}
+	return summary
+}
+
+// Normalize replaces the variable parts of a line so repeated errors group together.
+func Normalize(line string) string {
+	return strings.TrimSpace(variablePattern.ReplaceAllString(line, "N"))
+}
@@ -0,0 +1,66 @@
+package logstats
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	a := New(nil)
+	tests := []struct {
+		line  string
+		level string
+	}{
+		{`level=error msg="connection refused"`, LevelError},
+		{`{"level":"error","msg":"boom"}`, LevelError},
+		{`E0101 12:00:00.000000 1 reflector.go:1] failed`, LevelError},
+		{`panic: runtime error`, LevelError},
+		{`level=warn msg="slow request"`, LevelWarn},
+		{`W0101 12:00:00.000000 1 main.go:1] retrying`, LevelWarn},
+		{`level=info msg="served request"`, LevelOther},
+		{`errorless line about terrors`, LevelOther},
+	}
+	for _, tt := range tests {
+		if got := a.Classify(tt.line); got != tt.level {
+			t.Errorf("%q: expected %s, got %s", tt.line, tt.level, got)
+		}
+	}
+
+	custom := New([]Rule{{Level: LevelError, Pattern: regexp.MustCompile(`status=5\d\d`)}})
+	if got := custom.Classify("status=503 path=/"); got != LevelError {
+		t.Errorf("expected custom rule to match, got %s", got)
+	}
+	if got := custom.Classify("level=error"); got != LevelOther {
+		t.Errorf("expected custom rules to replace the defaults, got %s", got)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	a := New(nil)
+	a.Add("web-1", "level=error msg=\"timeout after 30s\" request=1234")
+	a.Add("web-1", "level=error msg=\"timeout after 31s\" request=99")
+	a.Add("web-2", "level=error msg=\"db unavailable\"")
+	a.Add("web-2", "level=info msg=ok")
+
+	summary := a.Summary(1)
+	if summary.Lines != 4 || summary.Levels[LevelError] != 3 || summary.Levels[LevelOther] != 1 {
+		t.Fatalf("unexpected counts: %+v", summary)
+	}
+	if summary.ErrorRate != 0.75 {
+		t.Errorf("expected error rate 0.75, got %v", summary.ErrorRate)
+	}
+	if len(summary.TopMessages) != 1 || summary.TopMessages[0].Count != 2 {
+		t.Errorf("expected the grouped timeout message first, got %+v", summary.TopMessages)
+	}
+	if summary.Sources[0].Source != "web-1" || summary.Sources[0].Errors != 2 {
+		t.Errorf("expected web-1 to have the most errors, got %+v", summary.Sources)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	got := Normalize("2024-05-01T10:00:00.123Z request 7f3c2a9b-1c2d-4e5f-8a9b-0c1d2e3f4a5b failed after 120ms")
+	want := "N request N failed after Nms"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,69 @@
+package manifest
+
+import (
+	"encoding/json"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+)
+
+// AnnotationPrefix is the prefix of the annotations the CLI records on objects.
+const AnnotationPrefix = "k8s-controller/"
+
+// Annotation returns the value of an annotation of an untyped object.
+func Annotation(obj map[string]interface{}, key string) (string, bool) {
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	annotations, _ := metadata["annotations"].(map[string]interface{})
+	value, ok := annotations[key].(string)
+	return value, ok
+}
+
+// SetAnnotation sets an annotation on an untyped object, creating metadata.annotations as needed.
+func SetAnnotation(obj map[string]interface{}, key, value string) {
+	metadata, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		metadata = map[string]interface{}{}
+		obj["metadata"] = metadata
+	}
+	annotations, ok := metadata["annotations"].(map[string]interface{})
+	if !ok {
+		annotations = map[string]interface{}{}
+		metadata["annotations"] = annotations
+	}
+	annotations[key] = value
+}
+
+// Canonicalize returns the JSON encoding of obj without the annotations ignore
+// reports. encoding/json sorts map keys, so formatting and key order in the
+// source don't matter. obj itself is left untouched.
+func Canonicalize(obj map[string]interface{}, ignore func(key string) bool) ([]byte, error) {
+	clean := map[string]interface{}{}
+	for k, v := range obj {
+		clean[k] = v
+	}
+
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		cleanMetadata := map[string]interface{}{}
+		for k, v := range metadata {
+			cleanMetadata[k] = v
+		}
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			cleanAnnotations := map[string]interface{}{}
+			for k, v := range annotations {
+				if !ignore(k) {
+					cleanAnnotations[k] = v
+				}
+			}
+			delete(cleanMetadata, "annotations")
+			if len(cleanAnnotations) > 0 {
+				cleanMetadata["annotations"] = cleanAnnotations
+			}
+		}
+		clean["metadata"] = cleanMetadata
+	}
+
+	data, err := json.Marshal(clean)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeValidationFailed, err, "encoding object")
+	}
+	return data, nil
+}
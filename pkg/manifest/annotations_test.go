@@ -0,0 +1,38 @@
+package manifest
+
+import "testing"
+
+func TestCanonicalizeIgnoresAnnotations(t *testing.T) {
+	obj := map[string]interface{}{
+		"kind":     "ConfigMap",
+		"metadata": map[string]interface{}{"name": "app"},
+	}
+	plain, err := Canonicalize(obj, func(key string) bool { return key == "ignored" })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	SetAnnotation(obj, "ignored", "value")
+	if value, ok := Annotation(obj, "ignored"); !ok || value != "value" {
+		t.Fatalf("expected the annotation to be set, got %q", value)
+	}
+	annotated, err := Canonicalize(obj, func(key string) bool { return key == "ignored" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(annotated) != string(plain) {
+		t.Errorf("expected the ignored annotation to be left out, got %s and %s", plain, annotated)
+	}
+	if _, ok := Annotation(obj, "ignored"); !ok {
+		t.Error("expected Canonicalize to leave obj untouched")
+	}
+
+	SetAnnotation(obj, "team", "payments")
+	kept, err := Canonicalize(obj, func(key string) bool { return key == "ignored" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"kind":"ConfigMap","metadata":{"annotations":{"team":"payments"},"name":"app"}}`; string(kept) != expected {
+		t.Errorf("expected %s, got %s", expected, kept)
+	}
+}
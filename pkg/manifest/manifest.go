@@ -0,0 +1,97 @@
+// Package manifest splits and joins multi-document YAML manifests, the format
+// every file-consuming command accepts.
+package manifest
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// Documents returns the documents of a YAML (or JSON) manifest, skipping
+// documents that are empty or only hold comments.
+func Documents(data []byte) ([][]byte, error) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+
+	var docs [][]byte
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errs.Wrap(errs.CodeValidationFailed, err, "reading manifest")
+		}
+
+		var content interface{}
+		if err := yaml.Unmarshal(doc, &content); err != nil {
+			return nil, errs.Wrap(errs.CodeValidationFailed, err, "decoding document %d", len(docs))
+		}
+		if content == nil {
+			continue
+		}
+		docs = append(docs, doc)
+	}
+
+	if len(docs) == 0 {
+		return nil, errs.ValidationFailed("manifest contains no objects")
+	}
+	return docs, nil
+}
+
+// Join concatenates YAML documents with --- separators.
+func Join(docs [][]byte) []byte {
+	var out bytes.Buffer
+	for i, doc := range docs {
+		if i > 0 {
+			out.WriteString("---\n")
+		}
+		out.Write(doc)
+		if len(doc) > 0 && doc[len(doc)-1] != '\n' {
+			out.WriteByte('\n')
+		}
+	}
+	return out.Bytes()
+}
+
+// Marshal encodes a typed object as YAML without the fields the API server
+// fills in: status and null creationTimestamps.
+func Marshal(obj runtime.Object) ([]byte, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeUnknown, err, "converting %T", obj)
+	}
+	delete(content, "status")
+	pruneCreationTimestamps(content)
+
+	data, err := yaml.Marshal(content)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeUnknown, err, "encoding %T", obj)
+	}
+	return data, nil
+}
+
+// pruneCreationTimestamps removes null metadata.creationTimestamp fields, including those of nested templates.
+func pruneCreationTimestamps(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if metadata, ok := v["metadata"].(map[string]interface{}); ok {
+			if ts, found := metadata["creationTimestamp"]; found && ts == nil {
+				delete(metadata, "creationTimestamp")
+			}
+		}
+		for _, child := range v {
+			pruneCreationTimestamps(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			pruneCreationTimestamps(child)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDocuments(t *testing.T) {
+	data := []byte(`# leading comment
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+---
+---
+# only a comment
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b
+`)
+	docs, err := Documents(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(docs) != 2 || !strings.Contains(string(docs[0]), "name: a") || !strings.Contains(string(docs[1]), "name: b") {
+		t.Errorf("unexpected documents %q", docs)
+	}
+
+	if _, err := Documents([]byte("# nothing\n")); !errs.IsValidationFailed(err) {
+		t.Errorf("expected validation error for an empty manifest, got %v", err)
+	}
+	if _, err := Documents([]byte("a: [")); !errs.IsValidationFailed(err) {
+		t.Errorf("expected validation error for invalid YAML, got %v", err)
+	}
+}
+
+func TestJoin(t *testing.T) {
+	got := string(Join([][]byte{[]byte("a: 1"), []byte("b: 2\n")}))
+	if got != "a: 1\n---\nb: 2\n" {
+		t.Errorf("unexpected output %q", got)
+	}
+}
+
+func TestMarshalDropsServerFields(t *testing.T) {
+	pod := &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Image: "nginx"}}},
+	}
+	data, err := Marshal(pod)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, field := range []string{"status", "creationTimestamp"} {
+		if strings.Contains(string(data), field) {
+			t.Errorf("expected %s to be dropped:\n%s", field, data)
+		}
+	}
+}
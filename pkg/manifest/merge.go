@@ -0,0 +1,172 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+// objectKey identifies an object across manifests. The API version is left out
+// so an overlay written against another version of the same group still applies.
+type objectKey struct {
+	group     string
+	kind      string
+	namespace string
+	name      string
+}
+
+func (k objectKey) String() string {
+	name := k.name
+	if k.namespace != "" {
+		name = k.namespace + "/" + name
+	}
+	return k.kind + " " + name
+}
+
+type mergeEntry struct {
+	key     objectKey
+	version string
+	json    []byte
+}
+
+// Merge layers overlay manifests onto base, in order, without a cluster.
+// Objects are matched by group, kind, namespace and name; an overlay object
+// without a namespace matches the only object of that kind and name.
+// Built-in kinds use strategic merge, so list entries such as containers merge
+// by name; other kinds (custom resources) use JSON merge patch, which replaces
+// lists. Overlay objects with no match are appended, and an overlay object
+// carrying "$patch: delete" removes its match.
+func Merge(base []byte, overlays ...[]byte) ([]byte, error) {
+	entries, err := mergeEntries(base, "base")
+	if err != nil {
+		return nil, err
+	}
+
+	for i, overlay := range overlays {
+		patches, err := mergeEntries(overlay, fmt.Sprintf("overlay %d", i+1))
+		if err != nil {
+			return nil, err
+		}
+		for _, patch := range patches {
+			entries, err = applyOverlay(entries, patch)
+			if err != nil {
+				return nil, errs.Wrap(errs.CodeValidationFailed, err, "overlay %d, %s", i+1, patch.key)
+			}
+		}
+	}
+
+	docs := make([][]byte, 0, len(entries))
+	for _, entry := range entries {
+		doc, err := yaml.JSONToYAML(entry.json)
+		if err != nil {
+			return nil, errs.Wrap(errs.CodeUnknown, err, "encoding %s", entry.key)
+		}
+		docs = append(docs, doc)
+	}
+	return Join(docs), nil
+}
+
+func mergeEntries(data []byte, source string) ([]mergeEntry, error) {
+	docs, err := Documents(data)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeValidationFailed, err, "%s", source)
+	}
+
+	entries := make([]mergeEntry, 0, len(docs))
+	for i, doc := range docs {
+		raw, err := yaml.YAMLToJSON(doc)
+		if err != nil {
+			return nil, errs.Wrap(errs.CodeValidationFailed, err, "%s, document %d", source, i)
+		}
+		var meta struct {
+			APIVersion string `json:"apiVersion"`
+			Kind       string `json:"kind"`
+			Metadata   struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+		}
+		if err := json.Unmarshal(raw, &meta); err != nil {
+			return nil, errs.Wrap(errs.CodeValidationFailed, err, "%s, document %d", source, i)
+		}
+		if meta.APIVersion == "" || meta.Kind == "" || meta.Metadata.Name == "" {
+			return nil, errs.ValidationFailed("%s, document %d: apiVersion, kind and metadata.name are required", source, i)
+		}
+		gv, err := schema.ParseGroupVersion(meta.APIVersion)
+		if err != nil {
+			return nil, errs.Wrap(errs.CodeValidationFailed, err, "%s, document %d", source, i)
+		}
+		entries = append(entries, mergeEntry{
+			key:     objectKey{group: gv.Group, kind: meta.Kind, namespace: meta.Metadata.Namespace, name: meta.Metadata.Name},
+			version: gv.Version,
+			json:    raw,
+		})
+	}
+	return entries, nil
+}
+
+func applyOverlay(entries []mergeEntry, patch mergeEntry) ([]mergeEntry, error) {
+	match, err := findEntry(entries, patch.key)
+	if err != nil {
+		return nil, err
+	}
+
+	var directive struct {
+		Patch string `json:"$patch"`
+	}
+	if err := json.Unmarshal(patch.json, &directive); err != nil {
+		return nil, err
+	}
+	if directive.Patch == "delete" {
+		if match < 0 {
+			return nil, errs.NotFound("no object to delete")
+		}
+		return append(entries[:match], entries[match+1:]...), nil
+	}
+
+	if match < 0 {
+		return append(entries, patch), nil
+	}
+
+	target := &entries[match]
+	gvk := schema.GroupVersionKind{Group: target.key.group, Version: target.version, Kind: target.key.kind}
+	var merged []byte
+	if typed, err := scheme.Scheme.New(gvk); err == nil {
+		merged, err = strategicpatch.StrategicMergePatch(target.json, patch.json, typed)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		merged, err = jsonpatch.MergePatch(target.json, patch.json)
+		if err != nil {
+			return nil, err
+		}
+	}
+	target.json = merged
+	return entries, nil
+}
+
+// findEntry returns the index of the entry matching key, -1 when there is none.
+func findEntry(entries []mergeEntry, key objectKey) (int, error) {
+	match := -1
+	for i, entry := range entries {
+		candidate := entry.key
+		if key.namespace == "" {
+			candidate.namespace = ""
+		}
+		if candidate != key {
+			continue
+		}
+		if match >= 0 {
+			return -1, errs.ValidationFailed("matches several objects, set metadata.namespace")
+		}
+		match = i
+	}
+	return match, nil
+}
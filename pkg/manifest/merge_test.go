@@ -0,0 +1,132 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const mergeBase = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: payments
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.26
+        env:
+        - name: LOG_LEVEL
+          value: info
+      - name: proxy
+        image: envoy:1.30
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: gadget
+spec:
+  sizes: [small, large]
+  color: red
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: debug
+  namespace: payments
+`
+
+func TestMergeStrategic(t *testing.T) {
+	overlay := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.27
+        env:
+        - name: LOG_LEVEL
+          value: debug
+`
+	out, err := Merge([]byte(mergeBase), []byte(overlay))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	docs, err := Documents(out)
+	if err != nil || len(docs) != 3 {
+		t.Fatalf("expected 3 documents, got %d (%v)", len(docs), err)
+	}
+
+	var deployment appsv1.Deployment
+	if err := yaml.UnmarshalStrict(docs[0], &deployment); err != nil {
+		t.Fatalf("decoding deployment: %v", err)
+	}
+	containers := deployment.Spec.Template.Spec.Containers
+	if *deployment.Spec.Replicas != 3 || deployment.Namespace != "payments" {
+		t.Errorf("unexpected deployment %+v", deployment.ObjectMeta)
+	}
+	if len(containers) != 2 || containers[0].Image != "nginx:1.27" || containers[1].Image != "envoy:1.30" {
+		t.Errorf("expected containers to merge by name, got %+v", containers)
+	}
+	if len(containers[0].Env) != 1 || containers[0].Env[0].Value != "debug" {
+		t.Errorf("expected env to merge by name, got %+v", containers[0].Env)
+	}
+}
+
+func TestMergeCustomResourcesAndDirectives(t *testing.T) {
+	overlay := `apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: gadget
+spec:
+  sizes: [medium]
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: debug
+  namespace: payments
+$patch: delete
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`
+	out, err := Merge([]byte(mergeBase), []byte(overlay))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := string(out)
+	if !strings.Contains(text, "- medium") || strings.Contains(text, "- small") || !strings.Contains(text, "color: red") {
+		t.Errorf("expected JSON merge patch semantics for custom resources:\n%s", text)
+	}
+	if strings.Contains(text, "kind: ConfigMap") {
+		t.Errorf("expected the config map to be deleted:\n%s", text)
+	}
+	if !strings.HasSuffix(strings.TrimSpace(text), "name: web") || !strings.Contains(text, "kind: Service") {
+		t.Errorf("expected the new service to be appended:\n%s", text)
+	}
+}
+
+func TestMergeErrors(t *testing.T) {
+	missingKind := "apiVersion: v1\nmetadata:\n  name: x\n"
+	if _, err := Merge([]byte(mergeBase), []byte(missingKind)); !errs.IsValidationFailed(err) {
+		t.Errorf("expected validation error for an object without kind, got %v", err)
+	}
+
+	deleteMissing := "apiVersion: v1\nkind: Secret\nmetadata:\n  name: x\n$patch: delete\n"
+	if _, err := Merge([]byte(mergeBase), []byte(deleteMissing)); err == nil {
+		t.Errorf("expected deleting an unknown object to fail")
+	}
+}
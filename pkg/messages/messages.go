@@ -0,0 +1,120 @@
+// Package messages holds the user-facing strings printed by the CLI so they
+// are defined in one place and can be translated without touching commands.
+// Commands print labels, sentences and table headers from the catalog; the
+// data they describe (table rows, log lines, manifests) is printed as is.
+package messages
+
+import "fmt"
+
+// ID identifies a message in the catalog.
+type ID string
+
+const (
+	ClusterName      ID = "cluster.name"
+	ClusterVersion   ID = "cluster.version"
+	NodeCountDefined ID = "cluster.node_count"
+	UserEntry        ID = "users.entry"
+
+	ClustersHeader ID = "clusters.header"
+
+	AuthContext    ID = "auth.context"
+	AuthCluster    ID = "auth.cluster"
+	AuthUser       ID = "auth.user"
+	AuthCredential ID = "auth.credential"
+	AuthSubject    ID = "auth.subject"
+	AuthExpires    ID = "auth.expires"
+	AuthDetail     ID = "auth.detail"
+
+	RBACHeader ID = "rbac.header"
+
+	RestartTriggered ID = "restart.triggered"
+	RestartCompleted ID = "restart.completed"
+	RolloutStatus    ID = "restart.rollout_status"
+	RolloutPod       ID = "restart.rollout_pod"
+
+	RevisionHash        ID = "revision.hash"
+	RevisionReplicaSet  ID = "revision.replica_set"
+	RevisionLiveHash    ID = "revision.live_hash"
+	RevisionNumber      ID = "revision.number"
+	RevisionHashHeader  ID = "revision.hash_header"
+	RevisionCheckHeader ID = "revision.check_header"
+
+	LogSummaryLines          ID = "logs.summary_lines"
+	LogSummaryErrors         ID = "logs.summary_errors"
+	LogSummaryWarnings       ID = "logs.summary_warnings"
+	LogSummaryErrorRate      ID = "logs.summary_error_rate"
+	LogSummarySourcesHeader  ID = "logs.summary_sources_header"
+	LogSummaryMessagesHeader ID = "logs.summary_messages_header"
+
+	TelemetryEnabled  ID = "telemetry.enabled"
+	TelemetryEndpoint ID = "telemetry.endpoint"
+	TelemetryBuffer   ID = "telemetry.buffer"
+	TelemetryPending  ID = "telemetry.pending"
+
+	VersionNumber       ID = "version.number"
+	VersionCommit       ID = "version.commit"
+	VersionBuildDate    ID = "version.build_date"
+	VersionGo           ID = "version.go"
+	VersionFeatureGates ID = "version.feature_gates"
+)
+
+// catalog holds the default English messages, keyed by ID. Labels of the same
+// command are padded so their values line up.
+var catalog = map[ID]string{
+	ClusterName:      "Cluster name: %s",
+	ClusterVersion:   "Kubernetes version: %s",
+	NodeCountDefined: "Current number of nodes on cluster is: %d",
+	UserEntry:        "%s",
+
+	ClustersHeader: "CONTEXT\tSERVER\tREACHABLE\tVERSION\tNODES\tCONTROLLER\tERROR",
+
+	AuthContext:    "Context:    %s",
+	AuthCluster:    "Cluster:    %s",
+	AuthUser:       "User:       %s",
+	AuthCredential: "Credential: %s",
+	AuthSubject:    "Subject:    %s",
+	AuthExpires:    "Expires:    %s",
+	AuthDetail:     "Detail:     %s",
+
+	RBACHeader: "KIND\tSUBJECT\tBINDING\tROLE",
+
+	RestartTriggered: "%s restarted",
+	RestartCompleted: "%s restarted in %s",
+	RolloutStatus:    "%s: %s",
+	RolloutPod:       "  pod %s",
+
+	RevisionHash:        "Local hash: %s",
+	RevisionReplicaSet:  "ReplicaSet: %s",
+	RevisionLiveHash:    "Live hash:  %s",
+	RevisionNumber:      "Revision:   %s",
+	RevisionHashHeader:  "DEPLOYMENT\tHASH",
+	RevisionCheckHeader: "OBJECT\tSTATUS",
+
+	LogSummaryLines:          "Lines:      %d",
+	LogSummaryErrors:         "Errors:     %d",
+	LogSummaryWarnings:       "Warnings:   %d",
+	LogSummaryErrorRate:      "Error rate: %.2f%%",
+	LogSummarySourcesHeader:  "POD\tLINES\tERRORS",
+	LogSummaryMessagesHeader: "COUNT\tTOP ERROR MESSAGES",
+
+	TelemetryEnabled:  "Enabled:  %t",
+	TelemetryEndpoint: "Endpoint: %s",
+	TelemetryBuffer:   "Buffer:   %s",
+	TelemetryPending:  "Pending:  %d",
+
+	VersionNumber:       "Version:       %s",
+	VersionCommit:       "Commit:        %s",
+	VersionBuildDate:    "Build date:    %s",
+	VersionGo:           "Go version:    %s",
+	VersionFeatureGates: "Feature gates: %s",
+}
+
+// Get formats the message identified by id with args. Unknown IDs are
+// returned verbatim so a missing entry is visible rather than silent.
+func Get(id ID, args ...interface{}) string {
+	format, ok := catalog[id]
+	if !ok {
+		return string(id)
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,48 @@
+package messages
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	if got := Get(ClusterName, "prod"); got != "Cluster name: prod" {
+		t.Errorf("unexpected message %q", got)
+	}
+	if got := Get(RestartCompleted, "deployment/web", "42s"); got != "deployment/web restarted in 42s" {
+		t.Errorf("unexpected message %q", got)
+	}
+	if got := Get(LogSummaryErrorRate, 12.5); got != "Error rate: 12.50%" {
+		t.Errorf("unexpected message %q", got)
+	}
+}
+
+func TestGetUnknownID(t *testing.T) {
+	if got := Get(ID("missing.id"), 1); got != "missing.id" {
+		t.Errorf("expected the ID back for an unknown message, got %q", got)
+	}
+}
+
+func TestLabelsAreAligned(t *testing.T) {
+	groups := map[string][]ID{
+		"auth":      {AuthContext, AuthCluster, AuthUser, AuthCredential, AuthSubject, AuthExpires, AuthDetail},
+		"revision":  {RevisionHash, RevisionReplicaSet, RevisionLiveHash, RevisionNumber},
+		"logs":      {LogSummaryLines, LogSummaryErrors, LogSummaryWarnings, LogSummaryErrorRate},
+		"telemetry": {TelemetryEnabled, TelemetryEndpoint, TelemetryBuffer, TelemetryPending},
+		"version":   {VersionNumber, VersionCommit, VersionBuildDate, VersionGo, VersionFeatureGates},
+	}
+	for name, ids := range groups {
+		column := -1
+		for _, id := range ids {
+			format, ok := catalog[id]
+			if !ok {
+				t.Fatalf("%s: %s is missing from the catalog", name, id)
+			}
+			start := strings.Index(format, "%")
+			if column >= 0 && start != column {
+				t.Errorf("%s: value of %s starts at column %d, expected %d", name, id, start, column)
+			}
+			column = start
+		}
+	}
+}
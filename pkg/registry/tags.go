@@ -0,0 +1,60 @@
+// Package registry lists image tags from OCI/Docker registries and filters
+// them by semantic version constraints.
+package registry
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+)
+
+// ListTags returns all tags of the repository referenced by image, following
+// registry pagination. Credentials are read from the docker config
+// (~/.docker/config.json and credential helpers).
+func ListTags(ctx context.Context, image string) ([]string, error) {
+	repo, err := name.NewRepository(image)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeValidationFailed, err, "invalid image %q", image)
+	}
+
+	tags, err := remote.List(repo,
+		remote.WithContext(ctx),
+		remote.WithAuthFromKeychain(authn.DefaultKeychain),
+	)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeUnknown, err, "listing tags for %s", repo)
+	}
+	return tags, nil
+}
+
+// FilterTags keeps the tags that parse as semantic versions and satisfy
+// constraint, sorted from oldest to newest.
+func FilterTags(tags []string, constraint string) ([]string, error) {
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeValidationFailed, err, "invalid semver constraint %q", constraint)
+	}
+
+	versions := make([]*semver.Version, 0, len(tags))
+	for _, tag := range tags {
+		v, err := semver.NewVersion(tag)
+		if err != nil {
+			continue
+		}
+		if c.Check(v) {
+			versions = append(versions, v)
+		}
+	}
+	sort.Sort(semver.Collection(versions))
+
+	filtered := make([]string, 0, len(versions))
+	for _, v := range versions {
+		filtered = append(filtered, v.Original())
+	}
+	return filtered, nil
+}
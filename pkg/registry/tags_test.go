@@ -0,0 +1,63 @@
+package registry
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+)
+
+func TestFilterTags(t *testing.T) {
+	tags := []string{"latest", "1.27.1", "v1.25.0", "1.26.3-alpine", "1.26.3", "2.0.0", "main"}
+
+	got, err := FilterTags(tags, ">=1.25, <2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"v1.25.0", "1.26.3", "1.27.1"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestFilterTagsInvalidConstraint(t *testing.T) {
+	if _, err := FilterTags([]string{"1.0.0"}, "not a constraint"); !errs.IsValidationFailed(err) {
+		t.Errorf("expected validation error, got %v", err)
+	}
+}
+
+func TestListTags(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	for _, tag := range []string{"1.0.0", "1.1.0"} {
+		ref, err := name.ParseReference(u.Host + "/demo/app:" + tag)
+		if err != nil {
+			t.Fatalf("failed to parse reference: %v", err)
+		}
+		img, err := random.Image(64, 1)
+		if err != nil {
+			t.Fatalf("failed to create image: %v", err)
+		}
+		if err := remote.Write(ref, img); err != nil {
+			t.Fatalf("failed to push image: %v", err)
+		}
+	}
+
+	tags, err := ListTags(context.Background(), u.Host+"/demo/app")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(tags, []string{"1.0.0", "1.1.0"}) {
+		t.Errorf("unexpected tags %v", tags)
+	}
+}
@@ -0,0 +1,85 @@
+// Package revision computes the hashes that tie a running workload back to its
+// inputs: the pod-template-hash the Deployment controller labels ReplicaSets
+// with, and an input hash annotation recorded on generated manifests.
+package revision
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/manifest"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/dump"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"sigs.k8s.io/yaml"
+)
+
+// InputHashAnnotation records the hash of the manifest an object was created from.
+const InputHashAnnotation = manifest.AnnotationPrefix + "input-hash"
+
+// PodTemplateHash computes the pod-template-hash label value exactly like the
+// Deployment controller. The controller hashes the template as stored by the
+// API server, after defaulting, together with the Deployment's
+// status.collisionCount; a template read from a local manifest only matches
+// when it spells out every defaulted field.
+func PodTemplateHash(template *corev1.PodTemplateSpec, collisionCount *int32) string {
+	hasher := fnv.New32a()
+	fmt.Fprintf(hasher, "%v", dump.ForHash(*template))
+	if collisionCount != nil {
+		collisionCountBytes := make([]byte, 8)
+		binary.LittleEndian.PutUint32(collisionCountBytes, uint32(*collisionCount))
+		hasher.Write(collisionCountBytes)
+	}
+	return rand.SafeEncodeString(fmt.Sprint(hasher.Sum32()))
+}
+
+// InputHash returns the SHA-256 of obj's canonical JSON. The annotations the
+// CLI records, such as the input hash and the signature, are ignored, so
+// annotated, signed and plain manifests hash the same.
+func InputHash(obj map[string]interface{}) (string, error) {
+	canonical, err := manifest.Canonicalize(obj, isCLIAnnotation)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// AnnotateManifest sets InputHashAnnotation on every object of a multi-document manifest.
+func AnnotateManifest(data []byte) ([]byte, error) {
+	docs, err := manifest.Documents(data)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([][]byte, 0, len(docs))
+	for i, doc := range docs {
+		var obj map[string]interface{}
+		if err := yaml.Unmarshal(doc, &obj); err != nil {
+			return nil, errs.Wrap(errs.CodeValidationFailed, err, "decoding document %d", i)
+		}
+		sum, err := InputHash(obj)
+		if err != nil {
+			return nil, err
+		}
+		manifest.SetAnnotation(obj, InputHashAnnotation, sum)
+
+		encoded, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, errs.Wrap(errs.CodeUnknown, err, "encoding document %d", i)
+		}
+		out = append(out, encoded)
+	}
+	return manifest.Join(out), nil
+}
+
+// isCLIAnnotation reports whether key is one of the annotations the CLI adds
+// after the input hash is computed.
+func isCLIAnnotation(key string) bool {
+	return strings.HasPrefix(key, manifest.AnnotationPrefix)
+}
@@ -0,0 +1,120 @@
+package revision
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/manifest"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/signing"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func testTemplate(image string) *corev1.PodTemplateSpec {
+	return &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Image: image}}},
+	}
+}
+
+func TestPodTemplateHash(t *testing.T) {
+	hash := PodTemplateHash(testTemplate("nginx:1.27"), nil)
+	if hash == "" {
+		t.Fatal("expected a hash")
+	}
+	if again := PodTemplateHash(testTemplate("nginx:1.27"), nil); again != hash {
+		t.Errorf("hash is not deterministic: %s != %s", again, hash)
+	}
+	if other := PodTemplateHash(testTemplate("nginx:1.28"), nil); other == hash {
+		t.Error("expected a different hash for a different template")
+	}
+
+	one := int32(1)
+	withCollision := PodTemplateHash(testTemplate("nginx:1.27"), &one)
+	if withCollision == hash {
+		t.Error("expected the collision count to change the hash")
+	}
+	two := int32(2)
+	if PodTemplateHash(testTemplate("nginx:1.27"), &two) == withCollision {
+		t.Error("expected different collision counts to give different hashes")
+	}
+}
+
+func TestInputHashIgnoresAddedAnnotations(t *testing.T) {
+	obj := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "app"},
+		"data":       map[string]interface{}{"key": "value"},
+	}
+	plain, err := InputHash(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifest.SetAnnotation(obj, InputHashAnnotation, plain)
+	manifest.SetAnnotation(obj, signing.SignatureAnnotation, "signature")
+	annotated, err := InputHash(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if annotated != plain {
+		t.Errorf("expected annotations to be ignored, got %s and %s", plain, annotated)
+	}
+
+	obj["data"] = map[string]interface{}{"key": "other"}
+	changed, err := InputHash(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed == plain {
+		t.Error("expected a different hash once data changes")
+	}
+}
+
+func TestAnnotateManifest(t *testing.T) {
+	data := []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+data:
+  key: value
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+  annotations:
+    team: payments
+`)
+	annotated, err := AnnotateManifest(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	docs := strings.Split(string(annotated), "\n---\n")
+	if len(docs) != 2 {
+		t.Fatalf("expected 2 documents, got %d:\n%s", len(docs), annotated)
+	}
+	for i, doc := range docs {
+		var obj map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			t.Fatal(err)
+		}
+		value, ok := manifest.Annotation(obj, InputHashAnnotation)
+		if !ok {
+			t.Fatalf("document %d: missing %s", i, InputHashAnnotation)
+		}
+		expected, err := InputHash(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value != expected {
+			t.Errorf("document %d: annotation %s, expected %s", i, value, expected)
+		}
+	}
+	if !strings.Contains(docs[1], "team: payments") {
+		t.Errorf("expected existing annotations to be kept:\n%s", docs[1])
+	}
+}
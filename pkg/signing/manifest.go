@@ -0,0 +1,103 @@
+package signing
+
+import (
+	"bytes"
+	"encoding/base64"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/manifest"
+	"golang.org/x/crypto/ssh"
+	"sigs.k8s.io/yaml"
+)
+
+// SignatureAnnotation holds the base64 encoded signature of an object when signatures are embedded.
+const SignatureAnnotation = manifest.AnnotationPrefix + "signature"
+
+// SignManifest embeds a signature annotation into every object of a (multi-document) YAML manifest.
+func SignManifest(data []byte, signer ssh.Signer) ([]byte, error) {
+	objects, err := decodeObjects(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	for i, obj := range objects {
+		canonical, err := manifest.Canonicalize(obj, isSignatureAnnotation)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := Sign(canonical, signer, Namespace)
+		if err != nil {
+			return nil, err
+		}
+		manifest.SetAnnotation(obj, SignatureAnnotation, base64.StdEncoding.EncodeToString(raw))
+
+		encoded, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, errs.Wrap(errs.CodeUnknown, err, "encoding object %d", i)
+		}
+		if i > 0 {
+			out.WriteString("---\n")
+		}
+		out.Write(encoded)
+	}
+	return out.Bytes(), nil
+}
+
+// VerifyManifest checks the embedded signature annotation of every object in data.
+func VerifyManifest(data []byte, publicKey ssh.PublicKey) error {
+	objects, err := decodeObjects(data)
+	if err != nil {
+		return err
+	}
+
+	for i, obj := range objects {
+		encoded, ok := manifest.Annotation(obj, SignatureAnnotation)
+		if !ok {
+			return errs.ValidationFailed("object %d (%s) has no %s annotation", i, objectName(obj), SignatureAnnotation)
+		}
+		raw, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return errs.Wrap(errs.CodeValidationFailed, err, "object %d (%s): decoding signature", i, objectName(obj))
+		}
+		canonical, err := manifest.Canonicalize(obj, isSignatureAnnotation)
+		if err != nil {
+			return err
+		}
+		if err := Verify(canonical, raw, publicKey, Namespace); err != nil {
+			return errs.Wrap(errs.CodeValidationFailed, err, "object %d (%s)", i, objectName(obj))
+		}
+	}
+	return nil
+}
+
+// decodeObjects splits a YAML stream into objects, skipping empty documents.
+func decodeObjects(data []byte) ([]map[string]interface{}, error) {
+	docs, err := manifest.Documents(data)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]map[string]interface{}, 0, len(docs))
+	for i, doc := range docs {
+		var obj map[string]interface{}
+		if err := yaml.Unmarshal(doc, &obj); err != nil {
+			return nil, errs.Wrap(errs.CodeValidationFailed, err, "decoding document %d", i)
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// isSignatureAnnotation reports whether key is the annotation left out of the signed content.
+func isSignatureAnnotation(key string) bool {
+	return key == SignatureAnnotation
+}
+
+// objectName renders an object as Kind/name for error messages.
+func objectName(obj map[string]interface{}) string {
+	kind, _ := obj["kind"].(string)
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	return kind + "/" + name
+}
@@ -0,0 +1,91 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	"golang.org/x/crypto/ssh"
+)
+
+const testManifest = `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+  labels:
+    app: web
+spec:
+  containers:
+  - name: web
+    image: nginx:1.27
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  annotations:
+    team: payments
+spec:
+  selector:
+    app: web
+`
+
+func newTestSigner(t *testing.T) ssh.Signer {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatalf("failed to create signer: %v", err)
+	}
+	return signer
+}
+
+func TestDetachedSignature(t *testing.T) {
+	signer := newTestSigner(t)
+
+	raw, err := Sign([]byte(testManifest), signer, Namespace)
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	decoded, err := Dearmor(Armor(raw))
+	if err != nil {
+		t.Fatalf("failed to dearmor: %v", err)
+	}
+
+	if err := Verify([]byte(testManifest), decoded, signer.PublicKey(), Namespace); err != nil {
+		t.Errorf("expected signature to verify, got %v", err)
+	}
+	if err := Verify([]byte(testManifest+"\n"), decoded, signer.PublicKey(), Namespace); !errs.IsValidationFailed(err) {
+		t.Errorf("expected modified manifest to fail verification, got %v", err)
+	}
+	if err := Verify([]byte(testManifest), decoded, newTestSigner(t).PublicKey(), Namespace); !errs.IsValidationFailed(err) {
+		t.Errorf("expected other key to fail verification, got %v", err)
+	}
+}
+
+func TestEmbeddedSignature(t *testing.T) {
+	signer := newTestSigner(t)
+
+	signed, err := SignManifest([]byte(testManifest), signer)
+	if err != nil {
+		t.Fatalf("failed to sign manifest: %v", err)
+	}
+	if strings.Count(string(signed), SignatureAnnotation) != 2 {
+		t.Fatalf("expected both objects to be annotated, got:\n%s", signed)
+	}
+	if err := VerifyManifest(signed, signer.PublicKey()); err != nil {
+		t.Errorf("expected signed manifest to verify, got %v", err)
+	}
+
+	tampered := strings.Replace(string(signed), "nginx:1.27", "nginx:latest", 1)
+	if err := VerifyManifest([]byte(tampered), signer.PublicKey()); !errs.IsValidationFailed(err) {
+		t.Errorf("expected tampered manifest to fail verification, got %v", err)
+	}
+	if err := VerifyManifest([]byte(testManifest), signer.PublicKey()); !errs.IsValidationFailed(err) {
+		t.Errorf("expected unsigned manifest to fail verification, got %v", err)
+	}
+}
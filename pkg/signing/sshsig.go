@@ -0,0 +1,169 @@
+// Package signing signs and verifies manifests with SSH keys, using the
+// SSHSIG format understood by "ssh-keygen -Y sign/verify".
+package signing
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"strings"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/errs"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// Namespace scopes signatures to manifests so they can't be replayed as signatures for other purposes.
+	Namespace = "k8s-controller-manifest"
+
+	sigMagic     = "SSHSIG"
+	sigVersion   = 1
+	sigHashAlg   = "sha512"
+	sigPEMHeader = "SSH SIGNATURE"
+)
+
+// signedData is the structure that is actually signed, see PROTOCOL.sshsig.
+type signedData struct {
+	Namespace string
+	Reserved  string
+	HashAlg   string
+	Hash      string
+}
+
+// signatureBlob is the serialized signature, without the leading magic preamble.
+type signatureBlob struct {
+	Version   uint32
+	PublicKey string
+	Namespace string
+	Reserved  string
+	HashAlg   string
+	Signature string
+}
+
+func messageToSign(message []byte, namespace string) []byte {
+	hash := sha512.Sum512(message)
+	data := ssh.Marshal(signedData{
+		Namespace: namespace,
+		HashAlg:   sigHashAlg,
+		Hash:      string(hash[:]),
+	})
+	return append([]byte(sigMagic), data...)
+}
+
+// Sign returns the raw SSHSIG signature of message.
+func Sign(message []byte, signer ssh.Signer, namespace string) ([]byte, error) {
+	toSign := messageToSign(message, namespace)
+
+	var sig *ssh.Signature
+	var err error
+	//plain ssh-rsa signatures use SHA-1, which ssh-keygen rejects
+	if algSigner, ok := signer.(ssh.AlgorithmSigner); ok && signer.PublicKey().Type() == ssh.KeyAlgoRSA {
+		sig, err = algSigner.SignWithAlgorithm(nil, toSign, ssh.KeyAlgoRSASHA512)
+	} else {
+		sig, err = signer.Sign(nil, toSign)
+	}
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeUnknown, err, "signing message")
+	}
+
+	blob := ssh.Marshal(signatureBlob{
+		Version:   sigVersion,
+		PublicKey: string(signer.PublicKey().Marshal()),
+		Namespace: namespace,
+		HashAlg:   sigHashAlg,
+		Signature: string(ssh.Marshal(sig)),
+	})
+	return append([]byte(sigMagic), blob...), nil
+}
+
+// Verify checks that raw is a valid signature of message by publicKey.
+func Verify(message, raw []byte, publicKey ssh.PublicKey, namespace string) error {
+	if !bytes.HasPrefix(raw, []byte(sigMagic)) {
+		return errs.ValidationFailed("not an SSH signature")
+	}
+
+	var blob signatureBlob
+	if err := ssh.Unmarshal(raw[len(sigMagic):], &blob); err != nil {
+		return errs.Wrap(errs.CodeValidationFailed, err, "decoding signature")
+	}
+	if blob.Version != sigVersion {
+		return errs.ValidationFailed("unsupported signature version %d", blob.Version)
+	}
+	if blob.Namespace != namespace {
+		return errs.ValidationFailed("signature namespace %q does not match %q", blob.Namespace, namespace)
+	}
+	if blob.HashAlg != sigHashAlg {
+		return errs.ValidationFailed("unsupported signature hash algorithm %q", blob.HashAlg)
+	}
+	if !bytes.Equal([]byte(blob.PublicKey), publicKey.Marshal()) {
+		return errs.ValidationFailed("manifest was signed by a different key (%s)", fingerprint(blob.PublicKey))
+	}
+
+	var sig ssh.Signature
+	if err := ssh.Unmarshal([]byte(blob.Signature), &sig); err != nil {
+		return errs.Wrap(errs.CodeValidationFailed, err, "decoding signature")
+	}
+	if err := publicKey.Verify(messageToSign(message, namespace), &sig); err != nil {
+		return errs.Wrap(errs.CodeValidationFailed, err, "signature does not match the manifest")
+	}
+	return nil
+}
+
+// Armor encodes a raw signature in the PEM-like format written by ssh-keygen.
+func Armor(raw []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(raw)
+	var b strings.Builder
+	b.WriteString("-----BEGIN " + sigPEMHeader + "-----\n")
+	for len(encoded) > 70 {
+		b.WriteString(encoded[:70] + "\n")
+		encoded = encoded[70:]
+	}
+	b.WriteString(encoded + "\n")
+	b.WriteString("-----END " + sigPEMHeader + "-----\n")
+	return []byte(b.String())
+}
+
+// Dearmor decodes a signature produced by Armor or ssh-keygen.
+func Dearmor(armored []byte) ([]byte, error) {
+	block, _ := pem.Decode(armored)
+	if block == nil || block.Type != sigPEMHeader {
+		return nil, errs.ValidationFailed("no %s block found", sigPEMHeader)
+	}
+	return block.Bytes, nil
+}
+
+// LoadSigner reads an unencrypted SSH private key.
+func LoadSigner(path string) (ssh.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeNotFound, err, "reading private key")
+	}
+	signer, err := ssh.ParsePrivateKey(data)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeValidationFailed, err, "parsing private key %s", path)
+	}
+	return signer, nil
+}
+
+// LoadPublicKey reads an SSH public key in authorized_keys format.
+func LoadPublicKey(path string) (ssh.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeNotFound, err, "reading public key")
+	}
+	key, _, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeValidationFailed, err, "parsing public key %s", path)
+	}
+	return key, nil
+}
+
+func fingerprint(marshaledKey string) string {
+	key, err := ssh.ParsePublicKey([]byte(marshaledKey))
+	if err != nil {
+		return "unknown"
+	}
+	return ssh.FingerprintSHA256(key)
+}
@@ -0,0 +1,234 @@
+// Package telemetry records anonymous, opt-in usage events (which commands
+// and flags are used, and how they fail) to a local buffer file and flushes
+// them in batches to a configurable HTTP endpoint.
+package telemetry
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Event describes a single command invocation. It never contains argument or flag values.
+type Event struct {
+	Time       time.Time `json:"time"`
+	Command    string    `json:"command"`
+	Flags      []string  `json:"flags,omitempty"`
+	ErrorCode  string    `json:"error_code,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	Version    string    `json:"version"`
+	OS         string    `json:"os"`
+	Arch       string    `json:"arch"`
+}
+
+// Client buffers events in a newline-delimited JSON file and posts them to Endpoint.
+type Client struct {
+	BufferPath string
+	Endpoint   string
+	// FlushThreshold is the number of buffered events that triggers a flush on Record.
+	FlushThreshold int
+	// MaxEvents caps the buffer, the oldest events are dropped beyond it. Zero keeps everything.
+	MaxEvents int
+	// RetryAfter is how long Record waits after a failed flush before flushing again.
+	RetryAfter time.Duration
+	HTTPClient *http.Client
+}
+
+// NewClient returns a client with a short HTTP timeout so telemetry never slows commands down noticeably.
+func NewClient(bufferPath, endpoint string) *Client {
+	return &Client{
+		BufferPath:     bufferPath,
+		Endpoint:       endpoint,
+		FlushThreshold: 20,
+		MaxEvents:      1000,
+		RetryAfter:     10 * time.Minute,
+		HTTPClient:     &http.Client{Timeout: 3 * time.Second},
+	}
+}
+
+// Record appends the event to the buffer and flushes once enough events are
+// pending, unless a recent flush failed.
+func (c *Client) Record(ctx context.Context, event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.BufferPath), 0o755); err != nil {
+		return err
+	}
+	if err := appendFile(c.BufferPath, append(line, '\n')); err != nil {
+		return err
+	}
+
+	pending, err := c.Pending()
+	if err != nil {
+		return err
+	}
+	if c.MaxEvents > 0 && pending > c.MaxEvents {
+		if err := c.trim(); err != nil {
+			return err
+		}
+		pending = c.MaxEvents
+	}
+
+	if c.Endpoint == "" || pending < c.FlushThreshold || c.backingOff() {
+		return nil
+	}
+	return c.Flush(ctx)
+}
+
+// Pending returns the number of buffered events.
+func (c *Client) Pending() (int, error) {
+	events, err := readEvents(c.BufferPath)
+	return len(events), err
+}
+
+// Flush posts all buffered events as a JSON array. The buffer is moved aside
+// first so events recorded meanwhile by other commands start a new buffer;
+// on failure the batch is put back.
+func (c *Client) Flush(ctx context.Context) error {
+	if c.Endpoint == "" {
+		return fmt.Errorf("no telemetry endpoint configured")
+	}
+
+	batch := fmt.Sprintf("%s.%d.sending", c.BufferPath, time.Now().UnixNano())
+	if err := os.Rename(c.BufferPath, batch); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	events, err := readEvents(batch)
+	if err == nil && len(events) > 0 {
+		err = c.post(ctx, events)
+	}
+	if err != nil {
+		c.markFailure()
+		if requeueErr := c.requeue(batch); requeueErr != nil {
+			return fmt.Errorf("%w (restoring buffer: %v)", err, requeueErr)
+		}
+		return err
+	}
+
+	_ = os.Remove(c.backoffPath())
+	return os.Remove(batch)
+}
+
+func (c *Client) post(ctx context.Context, events []Event) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// requeue appends an unsent batch back to the buffer and removes it.
+func (c *Client) requeue(batch string) error {
+	data, err := os.ReadFile(batch)
+	if err != nil {
+		return err
+	}
+	if err := appendFile(c.BufferPath, data); err != nil {
+		return err
+	}
+	return os.Remove(batch)
+}
+
+// trim rewrites the buffer with only the newest MaxEvents events.
+func (c *Client) trim() error {
+	events, err := readEvents(c.BufferPath)
+	if err != nil || len(events) <= c.MaxEvents {
+		return err
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	events = events[len(events)-c.MaxEvents:]
+
+	var buf bytes.Buffer
+	for _, event := range events {
+		line, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		buf.Write(append(line, '\n'))
+	}
+	tmp := c.BufferPath + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.BufferPath)
+}
+
+func (c *Client) backoffPath() string {
+	return c.BufferPath + ".backoff"
+}
+
+// markFailure records when a flush failed, best effort.
+func (c *Client) markFailure() {
+	_ = os.WriteFile(c.backoffPath(), []byte(time.Now().UTC().Format(time.RFC3339)), 0o600)
+}
+
+// backingOff reports whether a flush failed less than RetryAfter ago.
+func (c *Client) backingOff() bool {
+	data, err := os.ReadFile(c.backoffPath())
+	if err != nil {
+		return false
+	}
+	failed, err := time.Parse(time.RFC3339, string(data))
+	return err == nil && time.Since(failed) < c.RetryAfter
+}
+
+func appendFile(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// readEvents loads the events of a buffer file, skipping lines that can't be decoded.
+func readEvents(path string) ([]Event, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
@@ -0,0 +1,158 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordBuffersWithoutEndpoint(t *testing.T) {
+	c := NewClient(filepath.Join(t.TempDir(), "telemetry.ndjson"), "")
+
+	for i := 0; i < 3; i++ {
+		if err := c.Record(context.Background(), Event{Command: "demo add-user"}); err != nil {
+			t.Fatalf("failed to record event: %v", err)
+		}
+	}
+
+	pending, err := c.Pending()
+	if err != nil {
+		t.Fatalf("failed to read buffer: %v", err)
+	}
+	if pending != 3 {
+		t.Errorf("expected 3 pending events, got %d", pending)
+	}
+}
+
+func TestRecordFlushesAtThreshold(t *testing.T) {
+	var received []Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	c := NewClient(filepath.Join(t.TempDir(), "telemetry.ndjson"), server.URL)
+	c.FlushThreshold = 2
+
+	if err := c.Record(context.Background(), Event{Command: "version"}); err != nil {
+		t.Fatalf("failed to record event: %v", err)
+	}
+	if len(received) != 0 {
+		t.Fatalf("expected no flush below threshold, got %d events", len(received))
+	}
+	if err := c.Record(context.Background(), Event{Command: "create-pod", Flags: []string{"name"}}); err != nil {
+		t.Fatalf("failed to record event: %v", err)
+	}
+
+	if len(received) != 2 || received[1].Command != "create-pod" {
+		t.Errorf("expected both events to be flushed, got %+v", received)
+	}
+	if pending, _ := c.Pending(); pending != 0 {
+		t.Errorf("expected empty buffer after flush, got %d events", pending)
+	}
+}
+
+func TestFlushKeepsBufferOnFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	c := NewClient(filepath.Join(t.TempDir(), "telemetry.ndjson"), server.URL)
+	if err := c.Record(context.Background(), Event{Command: "version"}); err != nil {
+		t.Fatalf("failed to record event: %v", err)
+	}
+
+	if err := c.Flush(context.Background()); err == nil {
+		t.Errorf("expected flush to fail")
+	}
+	if pending, _ := c.Pending(); pending != 1 {
+		t.Errorf("expected event to stay buffered, got %d", pending)
+	}
+}
+
+func TestRecordCapsBuffer(t *testing.T) {
+	c := NewClient(filepath.Join(t.TempDir(), "telemetry.ndjson"), "")
+	c.MaxEvents = 3
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		event := Event{Command: fmt.Sprintf("command-%d", i), Time: start.Add(time.Duration(i) * time.Second)}
+		if err := c.Record(context.Background(), event); err != nil {
+			t.Fatalf("failed to record event: %v", err)
+		}
+	}
+
+	events, err := readEvents(c.BufferPath)
+	if err != nil {
+		t.Fatalf("failed to read buffer: %v", err)
+	}
+	if len(events) != 3 || events[0].Command != "command-2" || events[2].Command != "command-4" {
+		t.Errorf("expected the 3 newest events, got %+v", events)
+	}
+}
+
+func TestRecordBacksOffAfterFailedFlush(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c := NewClient(filepath.Join(t.TempDir(), "telemetry.ndjson"), server.URL)
+	c.FlushThreshold = 1
+
+	if err := c.Record(context.Background(), Event{Command: "version"}); err == nil {
+		t.Fatal("expected the first flush to fail")
+	}
+	if err := c.Record(context.Background(), Event{Command: "version"}); err != nil {
+		t.Errorf("expected no flush while backing off, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+	if pending, _ := c.Pending(); pending != 2 {
+		t.Errorf("expected both events to stay buffered, got %d", pending)
+	}
+}
+
+func TestFlushKeepsEventsRecordedMeanwhile(t *testing.T) {
+	var c *Client
+	var received []Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		// another command records an event while the batch is in flight
+		if err := c.Record(context.Background(), Event{Command: "concurrent"}); err != nil {
+			t.Errorf("failed to record event: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	c = NewClient(filepath.Join(t.TempDir(), "telemetry.ndjson"), server.URL)
+	if err := c.Record(context.Background(), Event{Command: "version"}); err != nil {
+		t.Fatalf("failed to record event: %v", err)
+	}
+	if err := c.Flush(context.Background()); err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+
+	if len(received) != 1 || received[0].Command != "version" {
+		t.Errorf("expected only the buffered event to be sent, got %+v", received)
+	}
+	events, _ := readEvents(c.BufferPath)
+	if len(events) != 1 || events[0].Command != "concurrent" {
+		t.Errorf("expected the concurrent event to stay buffered, got %+v", events)
+	}
+}